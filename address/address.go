@@ -0,0 +1,115 @@
+// Package address encodes hashes and witness programs into addresses of any script type, through a registry of encoders
+package address
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/savardiego/cashline/keys"
+	"github.com/savardiego/cashline/segwit"
+)
+
+// AddressEncoder encodes a program (the hash for P2PKH and P2SH, the witness program for SegWit and Taproot) into an address
+type AddressEncoder interface {
+	Encode(program []byte) (string, error)
+}
+
+// P2PKHEncoder encodes a 20 bytes public key hash into a base58 P2PKH address
+type P2PKHEncoder struct {
+	Network keys.Network
+}
+
+// Encode returns the P2PKH address of a public key hash
+func (e P2PKHEncoder) Encode(program []byte) (string, error) {
+	if len(program) != 20 {
+		return "", fmt.Errorf("public key hash must be 20 bytes, got %d", len(program))
+	}
+	return base58.CheckEncode(program, e.Network.PubKeyHashVersion()), nil
+}
+
+// P2SHEncoder encodes a 20 bytes script hash into a base58 P2SH address
+type P2SHEncoder struct {
+	Network keys.Network
+}
+
+// Encode returns the P2SH address of a script hash
+func (e P2SHEncoder) Encode(program []byte) (string, error) {
+	if len(program) != 20 {
+		return "", fmt.Errorf("script hash must be 20 bytes, got %d", len(program))
+	}
+	return base58.CheckEncode(program, e.Network.ScriptHashVersion()), nil
+}
+
+// P2WPKHEncoder encodes a 20 bytes public key hash into a bech32 witness v0 address
+type P2WPKHEncoder struct {
+	Network keys.Network
+}
+
+// Encode returns the P2WPKH address of a public key hash
+func (e P2WPKHEncoder) Encode(program []byte) (string, error) {
+	if len(program) != 20 {
+		return "", fmt.Errorf("public key hash must be 20 bytes, got %d", len(program))
+	}
+	return segwit.Encode(e.Network.Bech32HRP(), 0, program)
+}
+
+// P2TREncoder encodes a 32 bytes x-only output key into a bech32m witness v1 address
+type P2TREncoder struct {
+	Network keys.Network
+}
+
+// Encode returns the P2TR address of an x-only output key
+func (e P2TREncoder) Encode(program []byte) (string, error) {
+	if len(program) != 32 {
+		return "", fmt.Errorf("taproot output key must be 32 bytes, got %d", len(program))
+	}
+	return segwit.Encode(e.Network.Bech32HRP(), 1, program)
+}
+
+type registryKey struct {
+	scriptType string
+	network    keys.Network
+}
+
+var (
+	registryMutex sync.RWMutex
+	registry      map[registryKey]AddressEncoder
+)
+
+func init() {
+	registry = make(map[registryKey]AddressEncoder)
+	for _, network := range keys.Networks {
+		registry[registryKey{keys.ScriptTypeP2PKH, network}] = P2PKHEncoder{network}
+		registry[registryKey{keys.ScriptTypeP2SH, network}] = P2SHEncoder{network}
+		registry[registryKey{keys.ScriptTypeP2WPKH, network}] = P2WPKHEncoder{network}
+		registry[registryKey{keys.ScriptTypeP2TR, network}] = P2TREncoder{network}
+	}
+}
+
+// RegisterEncoder adds (or replaces) the encoder used for a script type on a network
+func RegisterEncoder(scriptType string, network keys.Network, encoder AddressEncoder) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	registry[registryKey{scriptType, network}] = encoder
+}
+
+// Encoder returns the encoder registered for a script type on a network
+func Encoder(scriptType string, network keys.Network) (AddressEncoder, error) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	encoder, ok := registry[registryKey{scriptType, network}]
+	if !ok {
+		return nil, fmt.Errorf("no encoder registered for %v addresses on %v", scriptType, network)
+	}
+	return encoder, nil
+}
+
+// Encode returns the address of a program using the encoder registered for the script type on the network
+func Encode(scriptType string, network keys.Network, program []byte) (string, error) {
+	encoder, err := Encoder(scriptType, network)
+	if err != nil {
+		return "", err
+	}
+	return encoder.Encode(program)
+}
@@ -0,0 +1,66 @@
+package address
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/savardiego/cashline/keys"
+)
+
+func TestEncode(t *testing.T) {
+	hash, _ := hex.DecodeString("751e76e8199196d454941c45d1b3a323f1433bd6")
+	outputKey, _ := hex.DecodeString("79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	expected := []struct {
+		scriptType string
+		network    keys.Network
+		program    []byte
+		address    string
+	}{
+		{keys.ScriptTypeP2PKH, keys.MainNet, hash, "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH"},
+		{keys.ScriptTypeP2PKH, keys.TestNet, hash, "mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r"},
+		{keys.ScriptTypeP2SH, keys.MainNet, hash, "3CNHUhP3uyB9EUtRLsmvFUmvGdjGdkTxJw"},
+		{keys.ScriptTypeP2SH, keys.TestNet, hash, "2N3vVYSK5XRgVSGWy21PnsRmBUywSQNdCsf"},
+		{keys.ScriptTypeP2WPKH, keys.MainNet, hash, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"},
+		{keys.ScriptTypeP2WPKH, keys.TestNet, hash, "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx"},
+		{keys.ScriptTypeP2TR, keys.MainNet, outputKey, "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0"},
+	}
+	for _, v := range expected {
+		address, err := Encode(v.scriptType, v.network, v.program)
+		if err != nil {
+			t.Errorf("cannot encode %v address due to %v", v.scriptType, err)
+		}
+		if address != v.address {
+			t.Errorf("%v address on %v should be %v but is %v", v.scriptType, v.network, v.address, address)
+		}
+	}
+	if _, err := Encode(keys.ScriptTypeP2PKH, keys.MainNet, outputKey); err == nil {
+		t.Errorf("32 bytes program should be rejected for P2PKH")
+	}
+	if _, err := Encode(keys.ScriptTypeP2TR, keys.MainNet, hash); err == nil {
+		t.Errorf("20 bytes program should be rejected for P2TR")
+	}
+	if _, err := Encode("P2XYZ", keys.MainNet, hash); err == nil {
+		t.Errorf("unknown script type should be rejected")
+	}
+}
+
+type upperEncoder struct{}
+
+func (upperEncoder) Encode(program []byte) (string, error) {
+	return strings.ToUpper(hex.EncodeToString(program)), nil
+}
+
+func TestRegisterEncoder(t *testing.T) {
+	RegisterEncoder("HEX", keys.MainNet, upperEncoder{})
+	address, err := Encode("HEX", keys.MainNet, []byte{0xca, 0xfe})
+	if err != nil {
+		t.Errorf("cannot encode with custom encoder due to %v", err)
+	}
+	if address != "CAFE" {
+		t.Errorf("custom encoder should give CAFE but gave %v", address)
+	}
+	if _, err := Encoder("HEX", keys.TestNet); err == nil {
+		t.Errorf("custom encoder was registered only for mainnet")
+	}
+}
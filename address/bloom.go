@@ -0,0 +1,103 @@
+package address
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// bloomMagic and bloomVersion start a serialized BloomFilter
+const (
+	bloomMagic   = "CLBF"
+	bloomVersion = 1
+)
+
+// BloomFilter is a set of addresses (e.g. all the funded ones) in a fixed, small size. Lookups have no false negatives
+// but have false positives: an address reported as present may have never been added, with a probability
+// depending on the size of the filter and the number of addresses in it. A positive is a hint to check elsewhere, not a proof
+type BloomFilter struct {
+	bits   []byte
+	m      uint32 // number of bits
+	hashes uint8  // number of hash functions
+}
+
+// NewBloomFilter returns an empty filter of m bits probed by the given number of hash functions
+func NewBloomFilter(m uint32, hashes uint8) (*BloomFilter, error) {
+	if m == 0 || hashes == 0 {
+		return nil, errors.New("bloom filter must have at least one bit and one hash function")
+	}
+	return &BloomFilter{bits: make([]byte, (m+7)/8), m: m, hashes: hashes}, nil
+}
+
+// Add adds an address to the filter
+func (f *BloomFilter) Add(address string) {
+	for _, position := range f.positions(address) {
+		f.bits[position/8] |= 1 << (position % 8)
+	}
+}
+
+// WriteTo writes the filter as magic(4) + version(1) + hashes(1) + bits count(4, big endian) + bits
+func (f *BloomFilter) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	buf.WriteString(bloomMagic)
+	buf.WriteByte(bloomVersion)
+	buf.WriteByte(f.hashes)
+	size := make([]byte, 4)
+	binary.BigEndian.PutUint32(size, f.m)
+	buf.Write(size)
+	buf.Write(f.bits)
+	return buf.WriteTo(w)
+}
+
+// LoadBloomFilter reads a filter written by BloomFilter.WriteTo
+func LoadBloomFilter(r io.Reader) (*BloomFilter, error) {
+	header := make([]byte, len(bloomMagic)+6)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("cannot read bloom filter header due to %v", err)
+	}
+	if string(header[:4]) != bloomMagic {
+		return nil, errors.New("not a bloom filter")
+	}
+	if header[4] != bloomVersion {
+		return nil, fmt.Errorf("bloom filter version %d not supported", header[4])
+	}
+	m, hashes := binary.BigEndian.Uint32(header[6:]), header[5]
+	if m == 0 || hashes == 0 {
+		return nil, errors.New("bloom filter must have at least one bit and one hash function")
+	}
+	// the bits grow with the data actually read, a header claiming up to 512 MiB cannot allocate them upfront
+	size := (int64(m) + 7) / 8
+	var bits bytes.Buffer
+	if _, err := io.Copy(&bits, io.LimitReader(r, size)); err != nil {
+		return nil, fmt.Errorf("cannot read bloom filter bits due to %v", err)
+	}
+	if int64(bits.Len()) != size {
+		return nil, fmt.Errorf("bloom filter has %d bytes of bits, header says %d", bits.Len(), size)
+	}
+	return &BloomFilter{bits: bits.Bytes(), m: m, hashes: hashes}, nil
+}
+
+// AddressInBloomFilter tells if an address may be in the filter: false is certain, true may be a false positive
+func AddressInBloomFilter(address string, filter *BloomFilter) bool {
+	for _, position := range filter.positions(address) {
+		if filter.bits[position/8]&(1<<(position%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// positions returns the bits of an address, by double hashing the two halves of the first 16 bytes of its sha256
+func (f *BloomFilter) positions(address string) []uint32 {
+	hash := sha256.Sum256([]byte(address))
+	h1 := binary.BigEndian.Uint64(hash[0:8])
+	h2 := binary.BigEndian.Uint64(hash[8:16])
+	positions := make([]uint32, f.hashes)
+	for i := range positions {
+		positions[i] = uint32((h1 + uint64(i)*h2) % uint64(f.m))
+	}
+	return positions
+}
@@ -0,0 +1,62 @@
+package address
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestBloomFilter(t *testing.T) {
+	funded := []string{
+		"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH",
+		"3CNHUhP3uyB9EUtRLsmvFUmvGdjGdkTxJw",
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+	}
+	filter, err := NewBloomFilter(1024, 7)
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	for _, address := range funded {
+		filter.Add(address)
+	}
+	var buf bytes.Buffer
+	if _, err := filter.WriteTo(&buf); err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	loaded, err := LoadBloomFilter(&buf)
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	for _, address := range funded {
+		if !AddressInBloomFilter(address, loaded) {
+			t.Errorf("address %v should be in the filter", address)
+		}
+	}
+	// with 3 addresses in 1024 bits false positives are very unlikely
+	falsePositives := 0
+	for i := 0; i < 1000; i++ {
+		if AddressInBloomFilter(fmt.Sprintf("not funded %d", i), loaded) {
+			falsePositives++
+		}
+	}
+	if falsePositives > 5 {
+		t.Errorf("too many false positives: %d", falsePositives)
+	}
+}
+
+func TestLoadBloomFilterErrors(t *testing.T) {
+	wrong := [][]byte{
+		nil,
+		[]byte("XXXX\x01\x07\x00\x00\x00\x08\x00"),
+		[]byte("CLBF\x02\x07\x00\x00\x00\x08\x00"),
+		[]byte("CLBF\x01\x00\x00\x00\x00\x08\x00"),
+		[]byte("CLBF\x01\x07\x00\x00\x00\x10\x00"),
+		// 2^32-1 bits claimed, a few bytes given
+		[]byte("CLBF\x01\x07\xff\xff\xff\xff\x00\x01\x02"),
+	}
+	for _, w := range wrong {
+		if _, err := LoadBloomFilter(bytes.NewReader(w)); err == nil {
+			t.Errorf("filter %q should not be loaded", w)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package address
+
+import (
+	"fmt"
+
+	"github.com/savardiego/cashline/keys"
+)
+
+// ConvertAddressType re-encodes the public key hash of a P2PKH or P2WPKH address as an address of another type of the same key.
+// Meaningful targets are P2PKH, P2WPKH and P2SH-P2WPKH, which all commit to the hash160 of one public key.
+// P2SH-P2WPKH can be produced but not converted back (its hash commits to a script, not to the key), and neither
+// P2SH, P2WSH nor P2TR addresses can be converted because they do not carry a public key hash
+func ConvertAddressType(address string, toType string, network keys.Network) (string, error) {
+	fromType, hash, err := decode(address, network)
+	if err != nil {
+		return "", err
+	}
+	if fromType != keys.ScriptTypeP2PKH && fromType != keys.ScriptTypeP2WPKH {
+		return "", fmt.Errorf("cannot convert %v address: it does not carry a public key hash", fromType)
+	}
+	switch toType {
+	case keys.ScriptTypeP2PKH:
+		return P2PKHEncoder{network}.Encode(hash)
+	case keys.ScriptTypeP2WPKH:
+		return P2WPKHEncoder{network}.Encode(hash)
+	case keys.ScriptTypeP2SHP2WPKH:
+		// the P2SH script is the P2WPKH witness program 0x0014<hash>
+		return P2SHEncoder{network}.Encode(keys.Hashed(append([]byte{op0, 20}, hash...)))
+	default:
+		return "", fmt.Errorf("cannot convert a public key hash to %v address", toType)
+	}
+}
@@ -0,0 +1,49 @@
+package address
+
+import (
+	"testing"
+
+	"github.com/savardiego/cashline/keys"
+)
+
+func TestConvertAddressType(t *testing.T) {
+	expected := []struct {
+		address string
+		toType  string
+		network keys.Network
+		result  string
+	}{
+		{"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", keys.ScriptTypeP2WPKH, keys.MainNet, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"},
+		{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", keys.ScriptTypeP2PKH, keys.MainNet, "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH"},
+		{"mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r", keys.ScriptTypeP2WPKH, keys.TestNet, "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx"},
+		{"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", keys.ScriptTypeP2PKH, keys.MainNet, "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH"},
+		{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", keys.ScriptTypeP2SHP2WPKH, keys.MainNet, "3JvL6Ymt8MVWiCNHC7oWU6nLeHNJKLZGLN"},
+	}
+	for _, v := range expected {
+		result, err := ConvertAddressType(v.address, v.toType, v.network)
+		if err != nil {
+			t.Errorf("cannot convert %v to %v due to %v", v.address, v.toType, err)
+			continue
+		}
+		if result != v.result {
+			t.Errorf("%v as %v should be %v but is %v", v.address, v.toType, v.result, result)
+		}
+	}
+	wrong := []struct {
+		address string
+		toType  string
+		network keys.Network
+	}{
+		{"3CNHUhP3uyB9EUtRLsmvFUmvGdjGdkTxJw", keys.ScriptTypeP2PKH, keys.MainNet},
+		{"bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr", keys.ScriptTypeP2WPKH, keys.MainNet},
+		{"bc1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3qccfmv3", keys.ScriptTypeP2PKH, keys.MainNet},
+		{"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", keys.ScriptTypeP2TR, keys.MainNet},
+		{"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", keys.ScriptTypeP2SH, keys.MainNet},
+		{"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", keys.ScriptTypeP2WPKH, keys.TestNet},
+	}
+	for _, w := range wrong {
+		if _, err := ConvertAddressType(w.address, w.toType, w.network); err == nil {
+			t.Errorf("converting %v to %v on %v should return an error", w.address, w.toType, w.network)
+		}
+	}
+}
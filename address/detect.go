@@ -0,0 +1,44 @@
+package address
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/savardiego/cashline/keys"
+	"github.com/savardiego/cashline/segwit"
+)
+
+// DetectNetwork returns the network of an address from its bech32 human readable part (bc, tb, bcrt) or its base58 version byte.
+// Signet shares both the human readable part and the version bytes of testnet, and regtest shares the version bytes of testnet,
+// so signet addresses and base58 regtest addresses are reported as TestNet: only bech32 regtest addresses are told apart
+func DetectNetwork(address string) (keys.Network, error) {
+	lower := strings.ToLower(address)
+	if separator := strings.LastIndexByte(lower, '1'); separator > 0 {
+		hrp := lower[:separator]
+		for _, network := range keys.Networks {
+			if hrp != network.Bech32HRP() {
+				continue
+			}
+			if _, _, err := segwit.Decode(hrp, address); err != nil {
+				return 0, fmt.Errorf("cannot decode segwit address %v due to %v", address, err)
+			}
+			return network, nil
+		}
+	}
+	hash, version, err := base58.CheckDecode(address)
+	if err != nil {
+		return 0, fmt.Errorf("address %v is neither bech32 with a known human readable part nor base58 due to %v", address, err)
+	}
+	if len(hash) != 20 {
+		return 0, fmt.Errorf("address hash must be 20 bytes, got %d", len(hash))
+	}
+	switch version {
+	case keys.MainNet.PubKeyHashVersion(), keys.MainNet.ScriptHashVersion():
+		return keys.MainNet, nil
+	case keys.TestNet.PubKeyHashVersion(), keys.TestNet.ScriptHashVersion():
+		return keys.TestNet, nil
+	default:
+		return 0, fmt.Errorf("unknown address version 0x%02x", version)
+	}
+}
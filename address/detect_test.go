@@ -0,0 +1,47 @@
+package address
+
+import (
+	"testing"
+
+	"github.com/savardiego/cashline/keys"
+)
+
+func TestDetectNetwork(t *testing.T) {
+	expected := []struct {
+		address string
+		network keys.Network
+	}{
+		{"1LoVGDgRs9hTfTNJNuXKSpywcbdvwRXpmK", keys.MainNet},
+		{"3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", keys.MainNet},
+		{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", keys.MainNet},
+		{"BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", keys.MainNet},
+		{"bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr", keys.MainNet},
+		{"n1KSZGmQgB8iSZqv6UVhGkCGUbEdw8Lm3Q", keys.TestNet},
+		{"2MzQwSSnBHWHqSAqtTVQ6v47XtaisrJa1Vc", keys.TestNet},
+		{"tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7", keys.TestNet},
+		{"bcrt1qw508d6qejxtdg4y5r3zarvary0c5xw7kygt080", keys.RegTest},
+	}
+	for _, v := range expected {
+		network, err := DetectNetwork(v.address)
+		if err != nil {
+			t.Errorf("cannot detect network of %v due to %v", v.address, err)
+			continue
+		}
+		if network != v.network {
+			t.Errorf("network of %v should be %v but is %v", v.address, v.network, network)
+		}
+	}
+	invalid := []string{
+		"",
+		"ltc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5",
+		"1LoVGDgRs9hTfTNJNuXKSpywcbdvwRXpmL",
+		// Litecoin P2PKH, version 0x30
+		"LVuDpNCSSj6pQ7t9Pv6d6sUkLKoqDEVUnJ",
+	}
+	for _, address := range invalid {
+		if _, err := DetectNetwork(address); err == nil {
+			t.Errorf("address %v should return an error", address)
+		}
+	}
+}
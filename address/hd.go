@@ -0,0 +1,160 @@
+package address
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/savardiego/cashline/keys"
+)
+
+// AddressChain is the BIP44 chain of an account: receive addresses are given to payers, change addresses get the change of payments
+type AddressChain uint32
+
+// Chains of a BIP44 account
+const (
+	ReceiveChain AddressChain = 0
+	ChangeChain  AddressChain = 1
+)
+
+// maxUnusedScan is how many addresses of a chain NextUnusedAddress derives before giving up
+const maxUnusedScan = 10000
+
+// FirstAddress returns the address of the first receive key (xpub/0/0) of an account extended key,
+// which wallets show after an import so that users can check it matches their wallet
+func FirstAddress(xpub *keys.ExtendedKey, scriptType string, network keys.Network) (string, error) {
+	receive, err := xpub.Child(0)
+	if err != nil {
+		return "", fmt.Errorf("cannot derive receive chain due to %v", err)
+	}
+	first, err := receive.Child(0)
+	if err != nil {
+		return "", fmt.Errorf("cannot derive first key due to %v", err)
+	}
+	return fromPubKey(first.PublicKey(), scriptType, network)
+}
+
+// AddressesBelongToXpub tells, for every address, whether it derives from the receive (0) or change (1) chain of an account extended key.
+// Each chain is scanned with the usual gap limit semantics: derivation goes on until gapLimit consecutive addresses match none of the given ones
+func AddressesBelongToXpub(addresses []string, xpub *keys.ExtendedKey, scriptType string, network keys.Network, gapLimit int) (map[string]bool, error) {
+	if gapLimit < 1 {
+		return nil, fmt.Errorf("gap limit must be at least 1, got %d", gapLimit)
+	}
+	belong := make(map[string]bool, len(addresses))
+	for _, address := range addresses {
+		belong[address] = false
+	}
+	found := 0
+	for _, chain := range []AddressChain{ReceiveChain, ChangeChain} {
+		chainKey, err := xpub.Child(uint32(chain))
+		if err != nil {
+			return nil, fmt.Errorf("cannot derive chain %d due to %v", chain, err)
+		}
+		for index, gap := uint32(0), 0; gap < gapLimit && found < len(belong); index++ {
+			child, err := chainKey.Child(index)
+			if err != nil {
+				// invalid children (probability below 2^-127) are skipped, as BIP32 prescribes
+				continue
+			}
+			address, err := fromPubKey(child.PublicKey(), scriptType, network)
+			if err != nil {
+				return nil, err
+			}
+			if matched, ok := belong[address]; ok && !matched {
+				belong[address] = true
+				found++
+				gap = 0
+			} else {
+				gap++
+			}
+		}
+	}
+	return belong, nil
+}
+
+// NextUnusedAddress returns the first address of a chain of an account extended key, with its index, that is not among the used ones:
+// the fresh receive (or change) address of a watch-only wallet. It gives up after maxUnusedScan addresses
+func NextUnusedAddress(xpub *keys.ExtendedKey, chain AddressChain, usedAddresses map[string]bool, scriptType string, network keys.Network) (address string, index uint32, err error) {
+	chainKey, err := xpub.Child(uint32(chain))
+	if err != nil {
+		return "", 0, fmt.Errorf("cannot derive chain %d due to %v", chain, err)
+	}
+	for index = 0; index < maxUnusedScan; index++ {
+		child, err := chainKey.Child(index)
+		if err != nil {
+			// invalid children (probability below 2^-127) are skipped, as BIP32 prescribes
+			continue
+		}
+		address, err = fromPubKey(child.PublicKey(), scriptType, network)
+		if err != nil {
+			return "", 0, err
+		}
+		if !usedAddresses[address] {
+			return address, index, nil
+		}
+	}
+	return "", 0, fmt.Errorf("the first %d addresses of chain %d are all used", maxUnusedScan, chain)
+}
+
+// DeriveAddressRange returns, in order, the addresses of the keys start to start+count-1 of a chain of an account extended key,
+// spreading the derivations over keys.OptimalWorkerCount() goroutines
+func DeriveAddressRange(xpub *keys.ExtendedKey, chain AddressChain, start, count uint32, scriptType string, network keys.Network) ([]string, error) {
+	return deriveAddressRange(xpub, chain, start, count, scriptType, network, keys.OptimalWorkerCount())
+}
+
+func deriveAddressRange(xpub *keys.ExtendedKey, chain AddressChain, start, count uint32, scriptType string, network keys.Network, workers int) ([]string, error) {
+	if uint64(start)+uint64(count) > uint64(keys.HardenedOffset) {
+		return nil, fmt.Errorf("range %d-%d goes beyond the non hardened indexes", start, uint64(start)+uint64(count)-1)
+	}
+	chainKey, err := xpub.Child(uint32(chain))
+	if err != nil {
+		return nil, fmt.Errorf("cannot derive chain %d due to %v", chain, err)
+	}
+	addresses := make([]string, count)
+	errs := make([]error, count)
+	indexes := make(chan uint32)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				child, err := chainKey.Child(start + i)
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+				addresses[i], errs[i] = fromPubKey(child.PublicKey(), scriptType, network)
+			}
+		}()
+	}
+	for i := uint32(0); i < count; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("cannot derive address %d due to %v", start+uint32(i), err)
+		}
+	}
+	return addresses, nil
+}
+
+// fromPubKey returns the single key address of a compressed public key for a script type
+func fromPubKey(pubKey []byte, scriptType string, network keys.Network) (string, error) {
+	switch scriptType {
+	case keys.ScriptTypeP2PKH, keys.ScriptTypeP2WPKH:
+		return Encode(scriptType, network, keys.Hashed(pubKey))
+	case keys.ScriptTypeP2SHP2WPKH:
+		redeemScript := append([]byte{op0, 20}, keys.Hashed(pubKey)...)
+		return Encode(keys.ScriptTypeP2SH, network, keys.Hashed(redeemScript))
+	case keys.ScriptTypeP2TR:
+		outputKey, err := keys.TaprootOutputKey(pubKey, nil)
+		if err != nil {
+			return "", err
+		}
+		return Encode(scriptType, network, outputKey)
+	default:
+		return "", fmt.Errorf("%v is not a single key script type", scriptType)
+	}
+}
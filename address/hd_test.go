@@ -0,0 +1,148 @@
+package address
+
+import (
+	"testing"
+
+	"github.com/savardiego/cashline/keys"
+)
+
+func TestFirstAddress(t *testing.T) {
+	// Account keys of the "abandon abandon ... about" mnemonic, from the BIP test vectors
+	expected := []struct {
+		xpub       string
+		scriptType string
+		address    string
+	}{
+		// https://github.com/bitcoin/bips/blob/master/bip-0084.mediawiki#test-vectors
+		{"zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs", keys.ScriptTypeP2WPKH, "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu"},
+		// https://github.com/bitcoin/bips/blob/master/bip-0049.mediawiki#test-vectors
+		{"ypub6Ww3ibxVfGzLrAH1PNcjyAWenMTbbAosGNB6VvmSEgytSER9azLDWCxoJwW7Ke7icmizBMXrzBx9979FfaHxHcrArf3zbeJJJUZPf663zsP", keys.ScriptTypeP2SHP2WPKH, "37VucYSaXLCAsxYyAPfbSi9eh4iEcbShgf"},
+		// https://github.com/bitcoin/bips/blob/master/bip-0086.mediawiki#test-vectors
+		{"xpub6BgBgsespWvERF3LHQu6CnqdvfEvtMcQjYrcRzx53QJjSxarj2afYWcLteoGVky7D3UKDP9QyrLprQ3VCECoY49yfdDEHGCtMMj92pReUsQ", keys.ScriptTypeP2TR, "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr"},
+		{"xpub6BosfCnifzxcFwrSzQiqu2DBVTshkCXacvNsWGYJVVhhawA7d4R5WSWGFNbi8Aw6ZRc1brxMyWMzG3DSSSSoekkudhUd9yLb6qx39T9nMdj", keys.ScriptTypeP2PKH, "1LqBGSKuX5yYUonjxT5qGfpUsXKYYWeabA"},
+	}
+	for _, v := range expected {
+		xpub, err := keys.ParseExtendedKey(v.xpub)
+		if err != nil {
+			t.Errorf("cannot parse %v due to %v", v.xpub, err)
+			continue
+		}
+		address, err := FirstAddress(xpub, v.scriptType, keys.MainNet)
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+			continue
+		}
+		if address != v.address {
+			t.Errorf("first %v address of %v should be %v but is %v", v.scriptType, v.xpub, v.address, address)
+		}
+	}
+	xpub, _ := keys.ParseExtendedKey(expected[0].xpub)
+	if _, err := FirstAddress(xpub, keys.ScriptTypeP2SH, keys.MainNet); err == nil {
+		t.Errorf("P2SH is not a single key script type and should return an error")
+	}
+}
+
+func TestAddressesBelongToXpub(t *testing.T) {
+	// https://github.com/bitcoin/bips/blob/master/bip-0084.mediawiki#test-vectors
+	xpub, _ := keys.ParseExtendedKey("zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs")
+	addresses := []string{
+		"bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu", // 0/0
+		"bc1q27yd7vz8m5kz230wuyncfe3pyazez6ah58yzy0", // 0/19
+		"bc1qerhm9gq6e8gfespketn85mynlp5hzdsuqqal0d", // 0/25, within the gap limit after 0/19
+		"bc1qu3936zt3c42xdz94752q07jg8656gfeh3agj6j", // 1/5
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", // not in the wallet
+	}
+	expected := []bool{true, true, true, true, false}
+	belong, err := AddressesBelongToXpub(addresses, xpub, keys.ScriptTypeP2WPKH, keys.MainNet, 20)
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	for i, address := range addresses {
+		if belong[address] != expected[i] {
+			t.Errorf("address %v should belong %v but is %v", address, expected[i], belong[address])
+		}
+	}
+	belong, err = AddressesBelongToXpub(addresses[:2], xpub, keys.ScriptTypeP2WPKH, keys.MainNet, 5)
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	if !belong[addresses[0]] || belong[addresses[1]] {
+		t.Errorf("with gap limit 5 only the first address should be found: %v", belong)
+	}
+	if _, err := AddressesBelongToXpub(addresses, xpub, keys.ScriptTypeP2WPKH, keys.MainNet, 0); err == nil {
+		t.Errorf("zero gap limit should return an error")
+	}
+}
+
+const bip84AccountXpub = "zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs"
+
+func TestDeriveAddressRange(t *testing.T) {
+	xpub, _ := keys.ParseExtendedKey(bip84AccountXpub)
+	addresses, err := DeriveAddressRange(xpub, ReceiveChain, 0, 30, keys.ScriptTypeP2WPKH, keys.MainNet)
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	expected := map[int]string{
+		0:  "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu",
+		1:  "bc1qnjg0jd8228aq7egyzacy8cys3knf9xvrerkf9g",
+		5:  "bc1qnpzzqjzet8gd5gl8l6gzhuc4s9xv0djt0rlu7a",
+		19: "bc1q27yd7vz8m5kz230wuyncfe3pyazez6ah58yzy0",
+		25: "bc1qerhm9gq6e8gfespketn85mynlp5hzdsuqqal0d",
+	}
+	for i, address := range expected {
+		if addresses[i] != address {
+			t.Errorf("address %d should be %v but is %v", i, address, addresses[i])
+		}
+	}
+	change, err := DeriveAddressRange(xpub, ChangeChain, 5, 1, keys.ScriptTypeP2WPKH, keys.MainNet)
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	if len(change) != 1 || change[0] != "bc1qu3936zt3c42xdz94752q07jg8656gfeh3agj6j" {
+		t.Errorf("change address 5 should be bc1qu3936zt3c42xdz94752q07jg8656gfeh3agj6j but is %v", change)
+	}
+	if _, err := DeriveAddressRange(xpub, ReceiveChain, keys.HardenedOffset-1, 2, keys.ScriptTypeP2WPKH, keys.MainNet); err == nil {
+		t.Errorf("range reaching hardened indexes should return an error")
+	}
+}
+
+func TestNextUnusedAddress(t *testing.T) {
+	xpub, _ := keys.ParseExtendedKey(bip84AccountXpub)
+	used := make(map[string]bool)
+	next, index, err := NextUnusedAddress(xpub, ReceiveChain, used, keys.ScriptTypeP2WPKH, keys.MainNet)
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	if next != "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu" || index != 0 {
+		t.Errorf("with no used addresses the first should be returned, got %v at %d", next, index)
+	}
+	addresses, _ := DeriveAddressRange(xpub, ReceiveChain, 0, 7, keys.ScriptTypeP2WPKH, keys.MainNet)
+	for i, address := range addresses {
+		// 5 is unused, 6 is used past the gap
+		used[address] = i != 5
+	}
+	next, index, err = NextUnusedAddress(xpub, ReceiveChain, used, keys.ScriptTypeP2WPKH, keys.MainNet)
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	if next != "bc1qnpzzqjzet8gd5gl8l6gzhuc4s9xv0djt0rlu7a" || index != 5 {
+		t.Errorf("next unused address should be bc1qnpzzqjzet8gd5gl8l6gzhuc4s9xv0djt0rlu7a at 5, got %v at %d", next, index)
+	}
+	if _, _, err := NextUnusedAddress(xpub, ReceiveChain, used, keys.ScriptTypeP2SH, keys.MainNet); err == nil {
+		t.Errorf("multisig script type should return an error")
+	}
+}
+
+func BenchmarkDeriveAddressRange(b *testing.B) {
+	xpub, _ := keys.ParseExtendedKey(bip84AccountXpub)
+	for i := 0; i < b.N; i++ {
+		DeriveAddressRange(xpub, ReceiveChain, 0, 100, keys.ScriptTypeP2WPKH, keys.MainNet)
+	}
+}
+
+func BenchmarkDeriveAddressRangeSequential(b *testing.B) {
+	xpub, _ := keys.ParseExtendedKey(bip84AccountXpub)
+	for i := 0; i < b.N; i++ {
+		deriveAddressRange(xpub, ReceiveChain, 0, 100, keys.ScriptTypeP2WPKH, keys.MainNet, 1)
+	}
+}
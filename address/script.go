@@ -0,0 +1,78 @@
+package address
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/savardiego/cashline/keys"
+	"github.com/savardiego/cashline/segwit"
+)
+
+// Script opcodes used by standard output scripts
+const (
+	opDup         = 0x76
+	opHash160     = 0xa9
+	opEqual       = 0x87
+	opEqualVerify = 0x88
+	opCheckSig    = 0xac
+	op0           = 0x00
+	op1           = 0x51
+)
+
+// ScriptPubKey returns the output script locking funds to an address of the network.
+// Supported address types are P2PKH, P2SH, P2WPKH, P2WSH and P2TR
+func ScriptPubKey(address string, network keys.Network) ([]byte, error) {
+	scriptType, program, err := decode(address, network)
+	if err != nil {
+		return nil, err
+	}
+	switch scriptType {
+	case keys.ScriptTypeP2PKH:
+		script := append([]byte{opDup, opHash160, 20}, program...)
+		return append(script, opEqualVerify, opCheckSig), nil
+	case keys.ScriptTypeP2SH:
+		script := append([]byte{opHash160, 20}, program...)
+		return append(script, opEqual), nil
+	case keys.ScriptTypeP2WPKH, keys.ScriptTypeP2WSH:
+		return append([]byte{op0, byte(len(program))}, program...), nil
+	default:
+		return append([]byte{op1, byte(len(program))}, program...), nil
+	}
+}
+
+// decode returns the script type and the program (hash, witness program or output key) of an address of the network
+func decode(address string, network keys.Network) (scriptType string, program []byte, err error) {
+	if strings.HasPrefix(strings.ToLower(address), network.Bech32HRP()+"1") {
+		version, program, err := segwit.Decode(network.Bech32HRP(), address)
+		if err != nil {
+			return "", nil, fmt.Errorf("cannot decode segwit address %v due to %v", address, err)
+		}
+		switch {
+		case version == 0 && len(program) == 20:
+			return keys.ScriptTypeP2WPKH, program, nil
+		case version == 0:
+			// 32 bytes, other lengths are already rejected by Decode
+			return keys.ScriptTypeP2WSH, program, nil
+		case version == 1 && len(program) == 32:
+			return keys.ScriptTypeP2TR, program, nil
+		default:
+			return "", nil, fmt.Errorf("unsupported witness v%d address with %d bytes program", version, len(program))
+		}
+	}
+	hash, version, err := base58.CheckDecode(address)
+	if err != nil {
+		return "", nil, fmt.Errorf("cannot decode address %v due to %v", address, err)
+	}
+	if len(hash) != 20 {
+		return "", nil, fmt.Errorf("address hash must be 20 bytes, got %d", len(hash))
+	}
+	switch version {
+	case network.PubKeyHashVersion():
+		return keys.ScriptTypeP2PKH, hash, nil
+	case network.ScriptHashVersion():
+		return keys.ScriptTypeP2SH, hash, nil
+	default:
+		return "", nil, fmt.Errorf("address version 0x%02x is not P2PKH or P2SH on %v", version, network)
+	}
+}
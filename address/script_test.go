@@ -0,0 +1,54 @@
+package address
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/savardiego/cashline/keys"
+)
+
+func TestScriptPubKey(t *testing.T) {
+	// Address, network, expected script
+	expected := []struct {
+		address string
+		network keys.Network
+		script  string
+	}{
+		{"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", keys.MainNet, "76a914751e76e8199196d454941c45d1b3a323f1433bd688ac"},
+		{"mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r", keys.TestNet, "76a914751e76e8199196d454941c45d1b3a323f1433bd688ac"},
+		{"3CNHUhP3uyB9EUtRLsmvFUmvGdjGdkTxJw", keys.MainNet, "a914751e76e8199196d454941c45d1b3a323f1433bd687"},
+		{"2N3vVYSK5XRgVSGWy21PnsRmBUywSQNdCsf", keys.TestNet, "a914751e76e8199196d454941c45d1b3a323f1433bd687"},
+		// https://github.com/bitcoin/bips/blob/master/bip-0173.mediawiki#test-vectors
+		{"BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", keys.MainNet, "0014751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{"tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7", keys.TestNet, "00201863143c14c5166804bd19203356da136c985678cd4d27a1b8c6329604903262"},
+		// https://github.com/bitcoin/bips/blob/master/bip-0086.mediawiki#test-vectors
+		{"bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr", keys.MainNet, "5120a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c"},
+	}
+	for _, v := range expected {
+		script, err := ScriptPubKey(v.address, v.network)
+		if err != nil {
+			t.Errorf("cannot get script of %v due to %v", v.address, err)
+			continue
+		}
+		if hex.EncodeToString(script) != v.script {
+			t.Errorf("script of %v should be %v but is %x", v.address, v.script, script)
+		}
+	}
+	wrong := []struct {
+		address string
+		network keys.Network
+	}{
+		{"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", keys.TestNet},
+		{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", keys.TestNet},
+		{"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMh", keys.MainNet},
+		{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5", keys.MainNet},
+		// witness v16 is valid bech32m but not a supported output type
+		{"bc1sw50qgdz25j", keys.MainNet},
+		{"", keys.MainNet},
+	}
+	for _, w := range wrong {
+		if _, err := ScriptPubKey(w.address, w.network); err == nil {
+			t.Errorf("address %v on %v should return an error", w.address, w.network)
+		}
+	}
+}
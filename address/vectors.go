@@ -0,0 +1,52 @@
+package address
+
+import (
+	"encoding/hex"
+
+	"github.com/savardiego/cashline/keys"
+)
+
+// TestVector holds the encodings of a private key computed by the package: the compressed mainnet WIF
+// and the P2PKH, P2WPKH and BIP86 P2TR mainnet addresses of its compressed public key
+type TestVector struct {
+	PrivateKey string
+	WIF        string
+	P2PKH      string
+	P2WPKH     string
+	P2TR       string
+}
+
+// testVectorKeys are the private keys of TestVectors: the smallest keys, a well known example key and the biggest key (n - 1)
+var testVectorKeys = []string{
+	"0000000000000000000000000000000000000000000000000000000000000001",
+	"0000000000000000000000000000000000000000000000000000000000000002",
+	"0000000000000000000000000000000000000000000000000000000000000003",
+	"0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d",
+	"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140",
+}
+
+// TestVectors returns a fixed list of private keys with their WIF and addresses, usable by integrators as golden data
+// to check that the encodings of the package have not changed. It panics if the package cannot encode them, which is a bug
+func TestVectors() []TestVector {
+	vectors := make([]TestVector, 0, len(testVectorKeys))
+	for _, privHex := range testVectorKeys {
+		privKey, _ := hex.DecodeString(privHex)
+		wif, err := keys.ToWIF(privKey, true)
+		if err != nil {
+			panic(err)
+		}
+		vector := TestVector{PrivateKey: privHex, WIF: wif}
+		pubKey := keys.Public(privKey, true)
+		for scriptType, field := range map[string]*string{
+			keys.ScriptTypeP2PKH:  &vector.P2PKH,
+			keys.ScriptTypeP2WPKH: &vector.P2WPKH,
+			keys.ScriptTypeP2TR:   &vector.P2TR,
+		} {
+			if *field, err = fromPubKey(pubKey, scriptType, keys.MainNet); err != nil {
+				panic(err)
+			}
+		}
+		vectors = append(vectors, vector)
+	}
+	return vectors
+}
@@ -0,0 +1,24 @@
+package address
+
+import "testing"
+
+func TestTestVectors(t *testing.T) {
+	// computed independently of the package, the P2WPKH of key 1 is also in BIP173
+	expected := []TestVector{
+		{"0000000000000000000000000000000000000000000000000000000000000001", "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn", "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", "bc1pmfr3p9j00pfxjh0zmgp99y8zftmd3s5pmedqhyptwy6lm87hf5sspknck9"},
+		{"0000000000000000000000000000000000000000000000000000000000000002", "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU74NMTptX4", "1cMh228HTCiwS8ZsaakH8A8wze1JR5ZsP", "bc1qq6hag67dl53wl99vzg42z8eyzfz2xlkvxechjp", "bc1pet7ep3czdu9k4wvdlz2fp5p8x2yp7t6ttyqg2c6cmh0lgeuu9lasmp9hsg"},
+		{"0000000000000000000000000000000000000000000000000000000000000003", "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU74sHUHy8S", "1CUNEBjYrCn2y1SdiUMohaKUi4wpP326Lb", "bc1q0ht9tyks4vh7p5p904t340cr9nvahy7u3re7zg", "bc1pgxxyvcmdncdxs06cudd5yvmwwahaesaj6n3eu7st7x4sw9hrchaqjy33gs"},
+		{"0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d", "KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617", "1LoVGDgRs9hTfTNJNuXKSpywcbdvwRXpmK", "bc1qmy63mjadtw8nhzl69ukdepwzsyvv4yex5qlmkd", "bc1pdj78vjhv4wukfzfu3qyvwclcewrsyfq8fyx2gvs8s850smsgw0yq8ykfa8"},
+		// n - 1 has the same x as key 1, so the same x-only Taproot output
+		{"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140", "L5oLkpV3aqBjhki6LmvChTCV6odsp4SXM6FfU2Gppt5kFLaHLuZ9", "1GrLCmVQXoyJXaPJQdqssNqwxvha1eUo2E", "bc1q4h0ycu78h88wzldxc7e79vhw5xsde0n8jk4wl5", "bc1pmfr3p9j00pfxjh0zmgp99y8zftmd3s5pmedqhyptwy6lm87hf5sspknck9"},
+	}
+	vectors := TestVectors()
+	if len(vectors) != len(expected) {
+		t.Fatalf("expected %d vectors, got %d", len(expected), len(vectors))
+	}
+	for i, v := range vectors {
+		if v != expected[i] {
+			t.Errorf("vector %d should be %+v but is %+v", i, expected[i], v)
+		}
+	}
+}
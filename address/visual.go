@@ -0,0 +1,49 @@
+package address
+
+import (
+	"crypto/sha256"
+	"errors"
+	"strings"
+)
+
+// visualCodeLength is the number of emoji in an address visual code
+const visualCodeLength = 6
+
+// visualPalette is the fixed set of emoji used for visual codes: 64 entries so that a byte maps to it without bias.
+// The order must never change, otherwise previously shown codes would no longer match
+var visualPalette = [64]string{
+	"🐶", "🐱", "🐭", "🐹", "🐰", "🦊", "🐻", "🐼",
+	"🐨", "🐯", "🦁", "🐮", "🐷", "🐸", "🐵", "🐔",
+	"🐧", "🐦", "🦆", "🦉", "🐺", "🐴", "🦄", "🐝",
+	"🐛", "🦋", "🐌", "🐞", "🐢", "🐍", "🐙", "🦀",
+	"🐬", "🐳", "🦈", "🐊", "🦒", "🐘", "🦔", "🌵",
+	"🌲", "🌻", "🍄", "🌙", "⭐", "🔥", "🌈", "❄",
+	"🍎", "🍋", "🍌", "🍉", "🍇", "🍓", "🍒", "🥕",
+	"🌽", "🍞", "🧀", "🍕", "🎈", "🔑", "⚓", "🚲",
+}
+
+// AddressVisualCode returns a short sequence of distinct emoji derived from the hash of an address,
+// which two parties can compare to confirm they are looking at the same address
+func AddressVisualCode(address string) (emojis []string, err error) {
+	address = strings.TrimSpace(address)
+	if address == "" {
+		return nil, errors.New("cannot compute visual code of an empty address")
+	}
+	used := make(map[int]bool)
+	hash := sha256.Sum256([]byte(address))
+	for len(emojis) < visualCodeLength {
+		for _, b := range hash {
+			index := int(b) % len(visualPalette)
+			if used[index] {
+				continue
+			}
+			used[index] = true
+			emojis = append(emojis, visualPalette[index])
+			if len(emojis) == visualCodeLength {
+				break
+			}
+		}
+		hash = sha256.Sum256(hash[:])
+	}
+	return emojis, nil
+}
@@ -0,0 +1,61 @@
+package address
+
+import (
+	"testing"
+)
+
+func TestAddressVisualCode(t *testing.T) {
+	addresses := []string{
+		"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH",
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		"3CNHUhP3uyB9EUtRLsmvFUmvGdjGdkTxJw",
+	}
+	codes := make(map[string]string)
+	for _, address := range addresses {
+		emojis, err := AddressVisualCode(address)
+		if err != nil {
+			t.Errorf("cannot compute visual code of %v: %v", address, err)
+			continue
+		}
+		if len(emojis) != visualCodeLength {
+			t.Errorf("visual code of %v has %d emoji, expected %d", address, len(emojis), visualCodeLength)
+		}
+		seen := make(map[string]bool)
+		code := ""
+		for _, e := range emojis {
+			if seen[e] {
+				t.Errorf("visual code of %v repeats %v", address, e)
+			}
+			seen[e] = true
+			code += e
+		}
+		again, _ := AddressVisualCode(address)
+		for i := range again {
+			if again[i] != emojis[i] {
+				t.Errorf("visual code of %v is not deterministic", address)
+				break
+			}
+		}
+		if other, ok := codes[code]; ok {
+			t.Errorf("addresses %v and %v share visual code %v", address, other, code)
+		}
+		codes[code] = address
+	}
+	// a stable expected code guards against palette reordering
+	emojis, _ := AddressVisualCode("1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH")
+	expected := []string{"🐺", "🐘", "🦈", "🦊", "⚓", "🐸"}
+	for i := range expected {
+		if emojis[i] != expected[i] {
+			t.Errorf("visual code changed: got %v, expected %v", emojis, expected)
+			break
+		}
+	}
+}
+
+func TestAddressVisualCodeError(t *testing.T) {
+	for _, address := range []string{"", "   "} {
+		if _, err := AddressVisualCode(address); err == nil {
+			t.Errorf("visual code of %q should fail", address)
+		}
+	}
+}
@@ -32,10 +32,13 @@ func FromPrivKeyHex(privKey string, compressed bool) (string, error) {
 
 // FromWIF derivates a legacy address (version 1, the oldest) from a base58 encoded WIF private key, compressed/uncompressed depending on the WIF format.
 func FromWIF(privKeyWIF string) (string, error) {
-	decodedPrivKey, compressed, err := keys.PrivateFromWIF(privKeyWIF)
+	decodedPrivKey, compressed, testnet, err := keys.PrivateFromWIF(privKeyWIF)
 	if err != nil {
 		return "", fmt.Errorf("cannot decode private key from base58 string: %v due to %v", privKeyWIF, err)
 	}
+	if testnet {
+		return "", fmt.Errorf("cannot derive a cashaddress from testnet key %v", privKeyWIF)
+	}
 	publicKey := keys.Public(decodedPrivKey, compressed)
 	withprefix, err := FromPubKey(publicKey)
 	return withprefix, nil
@@ -30,6 +30,8 @@ func main() {
 	flags[1] = keysSet
 	flags[2] = mnemonicSet
 
+	exitOnError(keys.VerifyHashers())
+
 	if len(os.Args) < 2 {
 		printDefaults(flags)
 		os.Exit(0)
@@ -139,7 +141,7 @@ func main() {
 				fmt.Printf("\nSequence must be of %d chars.\n\n", keys.HexSeqRequiredLength)
 				os.Exit(0)
 			}
-			key, err := hex.DecodeString(*hexMSequence)
+			key, err := keys.FromHexSequence(*hexMSequence)
 			exitOnError(err)
 			mn, err := keys.Mnemonic(key)
 			exitOnError(err)
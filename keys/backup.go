@@ -0,0 +1,53 @@
+package keys
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"github.com/tyler-smith/go-bip39/wordlists"
+)
+
+// BackupCheckWordsCount is the number of check words of a backup, 33 bits of the WIF hash
+const BackupCheckWordsCount = 3
+
+// BackupWithCheckWords returns the compressed WIF of a private key together with a few BIP39 words derived from its hash:
+// written down next to the WIF they let VerifyBackupCheckWords detect a transcription error, and are easier to compare than a checksum
+func BackupWithCheckWords(privKey []byte, network Network) (wif string, checkWords []string, err error) {
+	if len(privKey) != 32 {
+		return "", nil, fmt.Errorf("private key is %d bytes long, must be 32", len(privKey))
+	}
+	wif, err = ToWIFNetwork(privKey, true, network)
+	if err != nil {
+		return "", nil, err
+	}
+	return wif, backupCheckWords(wif), nil
+}
+
+// VerifyBackupCheckWords tells if the check words match a transcribed WIF (words are compared ignoring case and spaces around them)
+func VerifyBackupCheckWords(wif string, checkWords []string) (bool, error) {
+	if len(checkWords) != BackupCheckWordsCount {
+		return false, fmt.Errorf("got %d check words, must be %d", len(checkWords), BackupCheckWordsCount)
+	}
+	expected := backupCheckWords(strings.TrimSpace(wif))
+	match := true
+	for i, word := range checkWords {
+		if strings.ToLower(strings.TrimSpace(word)) != expected[i] {
+			match = false
+		}
+	}
+	return match, nil
+}
+
+// backupCheckWords maps the first 33 bits of the sha256 of a WIF to 3 words of the BIP39 wordlist
+func backupCheckWords(wif string) []string {
+	hash := sha256.Sum256([]byte(wif))
+	bits := uint64(hash[0])<<32 | uint64(hash[1])<<24 | uint64(hash[2])<<16 | uint64(hash[3])<<8 | uint64(hash[4])
+	wordList := wordlists.English
+	words := make([]string, BackupCheckWordsCount)
+	for i := range words {
+		index := (bits >> uint(40-11*(i+1))) & 0x7ff
+		words[i] = wordList[index]
+	}
+	return words
+}
@@ -0,0 +1,47 @@
+package keys
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestBackupWithCheckWords(t *testing.T) {
+	privKey, _ := hex.DecodeString("0C28FCA386C7A227600B2FE50B7CAE11EC86D3BF1FBE471BE89827E19D72AA1D")
+	wif, checkWords, err := BackupWithCheckWords(privKey, MainNet)
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	if wif != "KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617" {
+		t.Errorf("unexpected WIF %v", wif)
+	}
+	if len(checkWords) != BackupCheckWordsCount {
+		t.Fatalf("got %d check words, expected %d", len(checkWords), BackupCheckWordsCount)
+	}
+	ok, err := VerifyBackupCheckWords(wif, checkWords)
+	if err != nil || !ok {
+		t.Errorf("check words %v should match %v", checkWords, wif)
+	}
+	upper := []string{strings.ToUpper(checkWords[0]), " " + checkWords[1], checkWords[2]}
+	if ok, _ := VerifyBackupCheckWords(wif, upper); !ok {
+		t.Errorf("check words should match ignoring case and spaces")
+	}
+	// a single altered char of the WIF
+	altered := wif[:10] + "x" + wif[11:]
+	if ok, _ := VerifyBackupCheckWords(altered, checkWords); ok {
+		t.Errorf("check words should not match altered WIF %v", altered)
+	}
+	if _, err := VerifyBackupCheckWords(wif, checkWords[:2]); err == nil {
+		t.Errorf("wrong number of check words should return an error")
+	}
+	testnetWIF, testnetWords, _ := BackupWithCheckWords(privKey, TestNet)
+	if testnetWIF != "cMzLdeGd5vEqxB8B6VFQoRopQ3sLAAvEzDAoQgvX54xwofSWj1fx" {
+		t.Errorf("unexpected testnet WIF %v", testnetWIF)
+	}
+	if ok, _ := VerifyBackupCheckWords(testnetWIF, testnetWords); !ok {
+		t.Errorf("testnet check words should match")
+	}
+	if _, _, err := BackupWithCheckWords(privKey[1:], MainNet); err == nil {
+		t.Errorf("short key should return an error")
+	}
+}
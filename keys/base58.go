@@ -0,0 +1,52 @@
+package keys
+
+import (
+	"crypto/subtle"
+	"errors"
+)
+
+// base58Alphabet is the bitcoin base58 alphabet, without 0, O, I and l
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// Base58Alphabet returns the chars allowed in base58 encoded addresses and WIF keys (no 0, O, I, l), to validate input as it is typed
+func Base58Alphabet() string {
+	return base58Alphabet
+}
+
+// decodeBase58ConstantTime decodes a base58 string with a running time that depends on its length only, not on its chars:
+// every char is looked up scanning the whole alphabet and multiplies the whole output buffer, without early exits.
+// It is used for secrets such as WIF keys; the cost is a multiplication of the full buffer for every char, about 2000 byte operations
+// for a WIF, a few microseconds, several times slower than base58.Decode, which is fine for single keys but not for bulk decoding.
+// Only the number of leading zero bytes, which in a WIF is fixed by the version byte, affects the timing
+func decodeBase58ConstantTime(s string) ([]byte, error) {
+	// log(58) / log(256) is about 0.733
+	size := len(s)*733/1000 + 1
+	buffer := make([]byte, size)
+	invalid := 0
+	leadingOnes, counting := 0, 1
+	for i := 0; i < len(s); i++ {
+		digit, found := 0, 0
+		for d := 0; d < len(base58Alphabet); d++ {
+			match := subtle.ConstantTimeByteEq(s[i], base58Alphabet[d])
+			digit = subtle.ConstantTimeSelect(match, d, digit)
+			found |= match
+		}
+		invalid |= found ^ 1
+		counting &= subtle.ConstantTimeByteEq(s[i], '1')
+		leadingOnes += counting
+		carry := digit
+		for j := size - 1; j >= 0; j-- {
+			carry += int(buffer[j]) * 58
+			buffer[j] = byte(carry)
+			carry >>= 8
+		}
+	}
+	if invalid != 0 {
+		return nil, errors.New("input is not base58 encoded")
+	}
+	start := 0
+	for start < size && buffer[start] == 0 {
+		start++
+	}
+	return append(make([]byte, leadingOnes), buffer[start:]...), nil
+}
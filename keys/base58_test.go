@@ -0,0 +1,65 @@
+package keys
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+)
+
+func TestDecodeBase58ConstantTime(t *testing.T) {
+	inputs := []string{
+		"",
+		"1",
+		"111",
+		"z",
+		"5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ",
+		"KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617",
+		"cMzLdeGd5vEqxB8B6VFQoRopQ3sLAAvEzDAoQgvX54xwofSWj1fx",
+		"11LoVGDgRs9hTfTNJNuXKSpywcbdvwRXpmK",
+	}
+	source := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		data := make([]byte, source.Intn(40))
+		source.Read(data)
+		if len(data) > 0 && source.Intn(3) == 0 {
+			data[0] = 0
+		}
+		inputs = append(inputs, base58.Encode(data))
+	}
+	for _, input := range inputs {
+		decoded, err := decodeBase58ConstantTime(input)
+		if err != nil {
+			t.Errorf("failed to decode %v due to %v", input, err)
+		}
+		if expected := base58.Decode(input); !bytes.Equal(decoded, expected) {
+			t.Errorf("%v should decode to %x but decoded to %x", input, expected, decoded)
+		}
+	}
+	for _, input := range []string{"0", "KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP9861l", "abc def"} {
+		if _, err := decodeBase58ConstantTime(input); err == nil {
+			t.Errorf("%v should return an error", input)
+		}
+	}
+}
+
+func TestBase58Alphabet(t *testing.T) {
+	alphabet := Base58Alphabet()
+	if len(alphabet) != 58 {
+		t.Errorf("alphabet should have 58 chars but has %d", len(alphabet))
+	}
+	for _, c := range "0OIl" {
+		if strings.ContainsRune(alphabet, c) {
+			t.Errorf("alphabet should not contain %c", c)
+		}
+	}
+	for _, address := range []string{"1LoVGDgRs9hTfTNJNuXKSpywcbdvwRXpmK", "5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ"} {
+		for _, c := range address {
+			if !strings.ContainsRune(alphabet, c) {
+				t.Errorf("alphabet should contain %c of %v", c, address)
+			}
+		}
+	}
+}
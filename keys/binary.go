@@ -0,0 +1,96 @@
+package keys
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Binary encoding of keys: magic(1) + version(1) + kind(1) + payload
+const (
+	binaryMagic           = 0xCA
+	binaryVersion         = 1
+	binaryKindPrivateKey  = 0x01
+	binaryKindExtendedKey = 0x02
+)
+
+// PrivateKey is a private key with the network and the compression of the public key it is used with
+type PrivateKey struct {
+	Key        []byte
+	Compressed bool
+	Network    Network
+}
+
+// MarshalBinary encodes the private key in 37 bytes: header(3) + network(1) + compressed(1) + key(32)
+func (k *PrivateKey) MarshalBinary() ([]byte, error) {
+	if len(k.Key) != 32 || !isValidKey(new(big.Int).SetBytes(k.Key)) {
+		return nil, errors.New("key is not acceptable as private key")
+	}
+	data := []byte{binaryMagic, binaryVersion, binaryKindPrivateKey, byte(k.Network), 0}
+	if k.Compressed {
+		data[4] = 1
+	}
+	return append(data, k.Key...), nil
+}
+
+// UnmarshalBinary decodes a private key encoded by MarshalBinary
+func (k *PrivateKey) UnmarshalBinary(data []byte) error {
+	payload, err := binaryPayload(data, binaryKindPrivateKey, 34)
+	if err != nil {
+		return err
+	}
+	network := Network(payload[0])
+	if network.String() == "unknown" {
+		return fmt.Errorf("unknown network %d", payload[0])
+	}
+	if payload[1] > 1 {
+		return fmt.Errorf("invalid compression flag %d", payload[1])
+	}
+	if !isValidKey(new(big.Int).SetBytes(payload[2:])) {
+		return errors.New("encoded number is not acceptable as private key")
+	}
+	k.Network = network
+	k.Compressed = payload[1] == 1
+	k.Key = append([]byte{}, payload[2:]...)
+	return nil
+}
+
+// MarshalBinary encodes the extended key in 81 bytes: header(3) + BIP32 serialization(78), whose version tells the network
+func (k *ExtendedKey) MarshalBinary() ([]byte, error) {
+	serialized, err := k.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{binaryMagic, binaryVersion, binaryKindExtendedKey}, serialized...), nil
+}
+
+// UnmarshalBinary decodes an extended key encoded by MarshalBinary
+func (k *ExtendedKey) UnmarshalBinary(data []byte) error {
+	payload, err := binaryPayload(data, binaryKindExtendedKey, extendedKeyLength)
+	if err != nil {
+		return err
+	}
+	decoded, err := DeserializeExtendedKey(payload)
+	if err != nil {
+		return err
+	}
+	*k = *decoded
+	return nil
+}
+
+// binaryPayload checks the header of a binary encoded key and returns what follows it
+func binaryPayload(data []byte, kind byte, length int) ([]byte, error) {
+	if len(data) != 3+length {
+		return nil, fmt.Errorf("encoded key is %d bytes long, must be %d", len(data), 3+length)
+	}
+	if data[0] != binaryMagic {
+		return nil, fmt.Errorf("invalid magic byte %x", data[0])
+	}
+	if data[1] != binaryVersion {
+		return nil, fmt.Errorf("encoding version %d not supported", data[1])
+	}
+	if data[2] != kind {
+		return nil, fmt.Errorf("encoded key is of kind %d, expected %d", data[2], kind)
+	}
+	return data[3:], nil
+}
@@ -0,0 +1,76 @@
+package keys
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestPrivateKeyBinary(t *testing.T) {
+	key, _ := hex.DecodeString("0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d")
+	for _, network := range Networks {
+		for _, compressed := range []bool{true, false} {
+			original := &PrivateKey{Key: key, Compressed: compressed, Network: network}
+			data, err := original.MarshalBinary()
+			if err != nil {
+				t.Fatalf("failed due to %v\n", err)
+			}
+			decoded := &PrivateKey{}
+			if err := decoded.UnmarshalBinary(data); err != nil {
+				t.Fatalf("failed due to %v\n", err)
+			}
+			if !bytes.Equal(decoded.Key, key) || decoded.Compressed != compressed || decoded.Network != network {
+				t.Errorf("decoded key %+v differs from %+v", decoded, original)
+			}
+		}
+	}
+	// stable encoding
+	data, _ := (&PrivateKey{Key: key, Compressed: true, Network: TestNet}).MarshalBinary()
+	expected := "ca010101010c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d"
+	if hex.EncodeToString(data) != expected {
+		t.Errorf("encoding should be %v but is %x", expected, data)
+	}
+	wrong := []string{
+		"",
+		"cb010101010c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d",
+		"ca020101010c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d",
+		"ca010201010c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d",
+		"ca010109010c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d",
+		"ca010101020c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d",
+		"ca010101010000000000000000000000000000000000000000000000000000000000000000",
+		"ca010101010c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa",
+	}
+	for _, w := range wrong {
+		data, _ := hex.DecodeString(w)
+		if err := (&PrivateKey{}).UnmarshalBinary(data); err == nil {
+			t.Errorf("encoding %v should not be decoded", w)
+		}
+	}
+	if _, err := (&PrivateKey{Key: key[1:]}).MarshalBinary(); err == nil {
+		t.Errorf("short key should return an error")
+	}
+}
+
+func TestExtendedKeyBinary(t *testing.T) {
+	master, _ := ParseExtendedKey(bip32Vector1Master)
+	public, _ := master.Neuter()
+	for _, original := range []*ExtendedKey{master, public} {
+		data, err := original.MarshalBinary()
+		if err != nil {
+			t.Fatalf("failed due to %v\n", err)
+		}
+		decoded := &ExtendedKey{}
+		if err := decoded.UnmarshalBinary(data); err != nil {
+			t.Fatalf("failed due to %v\n", err)
+		}
+		expected, _ := original.Encode()
+		if s, _ := decoded.Encode(); s != expected {
+			t.Errorf("decoded key %v differs from %v", s, expected)
+		}
+	}
+	data, _ := master.MarshalBinary()
+	data[2] = binaryKindPrivateKey
+	if err := (&ExtendedKey{}).UnmarshalBinary(data); err == nil {
+		t.Errorf("wrong kind should return an error")
+	}
+}
@@ -0,0 +1,105 @@
+package keys
+
+import (
+	"crypto/aes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcutil/base58"
+	"golang.org/x/crypto/scrypt"
+)
+
+// BIP38 non EC multiply encrypted keys: prefix 0x0142, flag byte 0xC0 (0xE0 when compressed), scrypt parameters
+const (
+	bip38Length           = 39
+	bip38FlagUncompressed = 0xc0
+	bip38FlagCompressed   = 0xe0
+	bip38ScryptN          = 16384
+	bip38ScryptR          = 8
+	bip38ScryptP          = 8
+)
+
+// EncryptBIP38 encrypts a private key with a passphrase as BIP38 (non EC multiply) describes. The salt is the address hash,
+// the first 4 bytes of the double sha256 of the mainnet P2PKH address of the key (compressed or not), and scrypt (16384, 8, 8)
+// of passphrase and salt gives 64 bytes: the first half is XORed with the key, the second half is the AES-256 key encrypting it.
+// The result is the base58check of 0x01 0x42 || flag || address hash || encrypted key, starting with 6P
+func EncryptBIP38(privKey []byte, compressed bool, passphrase string) (string, error) {
+	if len(privKey) != 32 || !isValidKey(new(big.Int).SetBytes(privKey)) {
+		return "", errors.New("given key is not acceptable as private key")
+	}
+	salt := bip38AddressHash(privKey, compressed)
+	derived, err := scrypt.Key([]byte(passphrase), salt, bip38ScryptN, bip38ScryptR, bip38ScryptP, 64)
+	if err != nil {
+		return "", fmt.Errorf("cannot derive key due to %v", err)
+	}
+	block, err := aes.NewCipher(derived[32:])
+	if err != nil {
+		return "", fmt.Errorf("cannot create cipher due to %v", err)
+	}
+	encrypted := make([]byte, 32)
+	for i := range encrypted {
+		encrypted[i] = privKey[i] ^ derived[i]
+	}
+	block.Encrypt(encrypted[:16], encrypted[:16])
+	block.Encrypt(encrypted[16:], encrypted[16:])
+	flag := byte(bip38FlagUncompressed)
+	if compressed {
+		flag = bip38FlagCompressed
+	}
+	payload := append([]byte{0x42, flag}, salt...)
+	return base58.CheckEncode(append(payload, encrypted...), 0x01), nil
+}
+
+// DecryptBIP38 returns the private key of a BIP38 (non EC multiply) encrypted key, and whether its address uses the compressed public key.
+// A wrong passphrase is detected because the address of the decrypted key does not match the address hash
+func DecryptBIP38(encrypted, passphrase string) (privKey []byte, compressed bool, err error) {
+	decoded := base58.Decode(encrypted)
+	if len(decoded) != bip38Length+4 {
+		return nil, false, fmt.Errorf("invalid BIP38 key: decoded length %d, must be %d", len(decoded), bip38Length+4)
+	}
+	hashOne := sha256.Sum256(decoded[:bip38Length])
+	hashTwo := sha256.Sum256(hashOne[:])
+	if subtle.ConstantTimeCompare(hashTwo[:4], decoded[bip38Length:]) != 1 {
+		return nil, false, fmt.Errorf("cannot decode BIP38 key %v because checksum is wrong", encrypted)
+	}
+	if decoded[0] != 0x01 || decoded[1] != 0x42 {
+		return nil, false, fmt.Errorf("invalid BIP38 key: prefix %x is not a non EC multiply key (0142)", decoded[:2])
+	}
+	switch decoded[2] {
+	case bip38FlagUncompressed:
+	case bip38FlagCompressed:
+		compressed = true
+	default:
+		return nil, false, fmt.Errorf("invalid BIP38 key: unknown flag byte 0x%02x", decoded[2])
+	}
+	salt := decoded[3:7]
+	derived, err := scrypt.Key([]byte(passphrase), salt, bip38ScryptN, bip38ScryptR, bip38ScryptP, 64)
+	if err != nil {
+		return nil, false, fmt.Errorf("cannot derive key due to %v", err)
+	}
+	block, err := aes.NewCipher(derived[32:])
+	if err != nil {
+		return nil, false, fmt.Errorf("cannot create cipher due to %v", err)
+	}
+	privKey = make([]byte, 32)
+	block.Decrypt(privKey[:16], decoded[7:23])
+	block.Decrypt(privKey[16:], decoded[23:39])
+	for i := range privKey {
+		privKey[i] ^= derived[i]
+	}
+	if !isValidKey(new(big.Int).SetBytes(privKey)) || subtle.ConstantTimeCompare(bip38AddressHash(privKey, compressed), salt) != 1 {
+		return nil, false, errors.New("wrong passphrase: the decrypted key does not match the address hash")
+	}
+	return privKey, compressed, nil
+}
+
+// bip38AddressHash returns the first 4 bytes of the double sha256 of the mainnet P2PKH address of a private key
+func bip38AddressHash(privKey []byte, compressed bool) []byte {
+	address := base58.CheckEncode(Hashed(Public(privKey, compressed)), MainNet.PubKeyHashVersion())
+	hashOne := sha256.Sum256([]byte(address))
+	hashTwo := sha256.Sum256(hashOne[:])
+	return hashTwo[:4]
+}
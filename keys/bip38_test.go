@@ -0,0 +1,50 @@
+package keys
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestBIP38(t *testing.T) {
+	// https://github.com/bitcoin/bips/blob/master/bip-0038.mediawiki#no-compression-no-ec-multiply
+	// Private key, compressed, passphrase, encrypted key
+	expected := [][]string{
+		[]string{"cbf4b9f70470856bb4f40f80b87edb90865997ffee6df315ab166d713af433a5", "false", "TestingOneTwoThree", "6PRVWUbkzzsbcVac2qwfssoUJAN1Xhrg6bNk8J7Nzm5H7kxEbn2Nh2ZoGg"},
+		[]string{"09c2686880095b1a4c249ee3ac4eea8a014f11e6f986d0b5025ac1f39afbd9ae", "false", "Satoshi", "6PRNFFkZc2NZ6dJqFfhRoFNMR9Lnyj7dYGrzdgXXVMXcxoKTePPX1dWByq"},
+		[]string{"cbf4b9f70470856bb4f40f80b87edb90865997ffee6df315ab166d713af433a5", "true", "TestingOneTwoThree", "6PYNKZ1EAgYgmQfmNVamxyXVWHzK5s6DGhwP4J5o44cvXdoY7sRzhtpUeo"},
+		[]string{"09c2686880095b1a4c249ee3ac4eea8a014f11e6f986d0b5025ac1f39afbd9ae", "true", "Satoshi", "6PYLtMnXvfG3oJde97zRyLYFZCYizPU5T3LwgdYJz1fRhh16bU7u6PPmY7"},
+	}
+	for _, v := range expected {
+		privKey, _ := hex.DecodeString(v[0])
+		encrypted, err := EncryptBIP38(privKey, v[1] == "true", v[2])
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+			continue
+		}
+		if encrypted != v[3] {
+			t.Errorf("encrypted key of %v should be %v but is %v", v[0], v[3], encrypted)
+		}
+		decrypted, compressed, err := DecryptBIP38(v[3], v[2])
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+			continue
+		}
+		if hex.EncodeToString(decrypted) != v[0] || compressed != (v[1] == "true") {
+			t.Errorf("decrypted key of %v should be %v (compressed %v) but is %x (compressed %v)", v[3], v[0], v[1], decrypted, compressed)
+		}
+	}
+	_, _, err := DecryptBIP38(expected[0][3], "TestingOneTwoThre")
+	if err == nil || !strings.Contains(err.Error(), "wrong passphrase") {
+		t.Errorf("wrong passphrase should be reported, got %v", err)
+	}
+	wrong := []string{"", "6PRVWUbkzzsbcVac2qwfssoUJAN1Xhrg6bNk8J7Nzm5H7kxEbn2Nh2ZoGh", "KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617"}
+	for _, w := range wrong {
+		if _, _, err := DecryptBIP38(w, "TestingOneTwoThree"); err == nil {
+			t.Errorf("key %v should return an error", w)
+		}
+	}
+	if _, err := EncryptBIP38(make([]byte, 32), true, "Satoshi"); err == nil {
+		t.Errorf("zero key should return an error")
+	}
+}
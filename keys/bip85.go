@@ -0,0 +1,56 @@
+package keys
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+)
+
+// BIP85LanguageEnglish is the BIP85 code of the english wordlist, the only one supported
+const BIP85LanguageEnglish = 0
+
+// BIP85Mnemonic derives from a master private key the child BIP39 mnemonic of m/83696968'/39'/language'/words'/index':
+// every index gives an independent wallet that can be recreated from the master seed alone.
+// Reference: https://github.com/bitcoin/bips/blob/master/bip-0085.mediawiki
+func BIP85Mnemonic(master *ExtendedKey, language int, words int, index uint32) (string, error) {
+	if language != BIP85LanguageEnglish {
+		return "", fmt.Errorf("language %d not supported, only english (%d) is", language, BIP85LanguageEnglish)
+	}
+	var length int
+	switch words {
+	case 12:
+		length = 16
+	case 18:
+		length = 24
+	case 24:
+		length = 32
+	default:
+		return "", fmt.Errorf("%d words not supported, must be 12, 18 or 24", words)
+	}
+	if index >= HardenedOffset {
+		return "", fmt.Errorf("index %d is too big, give it without the hardened offset", index)
+	}
+	entropy, err := bip85Entropy(master, []uint32{83696968, 39, uint32(language), uint32(words), index})
+	if err != nil {
+		return "", err
+	}
+	return Mnemonic(entropy[:length])
+}
+
+// bip85Entropy returns the 64 bytes of entropy of a hardened path of a master private key
+func bip85Entropy(master *ExtendedKey, path []uint32) ([]byte, error) {
+	if master.Depth != 0 || !master.IsPrivate() {
+		return nil, errors.New("BIP85 entropy must be derived from a master private key")
+	}
+	key := master
+	var err error
+	for _, index := range path {
+		if key, err = key.Child(index + HardenedOffset); err != nil {
+			return nil, fmt.Errorf("cannot derive BIP85 key due to %v", err)
+		}
+	}
+	mac := hmac.New(sha512.New, []byte("bip-entropy-from-k"))
+	mac.Write(key.Key)
+	return mac.Sum(nil), nil
+}
@@ -0,0 +1,65 @@
+package keys
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// https://github.com/bitcoin/bips/blob/master/bip-0085.mediawiki#test-vectors
+const bip85Master = "xprv9s21ZrQH143K2LBWUUQRFXhucrQqBpKdRRxNVq2zBqsx8HVqFk2uYo8kmbaLLHRdqtQpUm98uKfu3vca1LqdGhUtyoFnCNkfmXRyPXLjbKb"
+
+func TestBIP85Entropy(t *testing.T) {
+	master, err := ParseExtendedKey(bip85Master)
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	// Path, expected entropy (truncated to the length used)
+	expected := []struct {
+		path    []uint32
+		entropy string
+	}{
+		{[]uint32{83696968, 0, 0}, "efecfbccffea313214232d29e71563d941229afb4338c21f9517c41aaa0d16f00b83d2a09ef747e7a64e8e2bd5a14869e693da66ce94ac2da570ab7ee48618f7"},
+		{[]uint32{83696968, 39, 0, 12, 0}, "6250b68daf746d12a24d58b4787a714b"},
+		{[]uint32{83696968, 39, 0, 18, 0}, "938033ed8b12698449d4bbca3c853c66b293ea1b1ce9d9dc"},
+		{[]uint32{83696968, 39, 0, 24, 0}, "ae131e2312cdc61331542efe0d1077bac5ea803adf24b313a4f0e48e9c51f37f"},
+	}
+	for _, v := range expected {
+		entropy, err := bip85Entropy(master, v.path)
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+			continue
+		}
+		if hex.EncodeToString(entropy[:len(v.entropy)/2]) != v.entropy {
+			t.Errorf("entropy of %v should be %v but is %x", v.path, v.entropy, entropy)
+		}
+	}
+}
+
+func TestBIP85Mnemonic(t *testing.T) {
+	master, _ := ParseExtendedKey(bip85Master)
+	for _, words := range []int{12, 18, 24} {
+		mnemonic, err := BIP85Mnemonic(master, BIP85LanguageEnglish, words, 0)
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+			continue
+		}
+		if len(strings.Fields(mnemonic)) != words {
+			t.Errorf("mnemonic should have %d words: %v", words, mnemonic)
+		}
+		other, _ := BIP85Mnemonic(master, BIP85LanguageEnglish, words, 1)
+		if other == mnemonic {
+			t.Errorf("mnemonics of different indexes should differ")
+		}
+	}
+	if _, err := BIP85Mnemonic(master, 1, 12, 0); err == nil {
+		t.Errorf("unsupported language should return an error")
+	}
+	if _, err := BIP85Mnemonic(master, BIP85LanguageEnglish, 15, 0); err == nil {
+		t.Errorf("unsupported words count should return an error")
+	}
+	child, _ := master.Child(HardenedOffset)
+	if _, err := BIP85Mnemonic(child, BIP85LanguageEnglish, 12, 0); err == nil {
+		t.Errorf("non master key should return an error")
+	}
+}
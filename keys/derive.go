@@ -0,0 +1,308 @@
+package keys
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcutil/base58"
+	bip39 "github.com/tyler-smith/go-bip39"
+)
+
+// HardenedOffset is added to a child index to derive a hardened child (written i' or iH in paths)
+const HardenedOffset uint32 = 0x80000000
+
+// ParseExtendedKey decodes a base58 encoded extended key (xprv, xpub, and the other supported prefixes)
+func ParseExtendedKey(s string) (*ExtendedKey, error) {
+	decoded := base58.Decode(s)
+	if len(decoded) != extendedKeyLength+4 {
+		return nil, fmt.Errorf("invalid extended key: decoded length %d, must be %d", len(decoded), extendedKeyLength+4)
+	}
+	payload := decoded[:extendedKeyLength]
+	hashOne := sha256.Sum256(payload)
+	hashTwo := sha256.Sum256(hashOne[:])
+	if subtle.ConstantTimeCompare(hashTwo[:4], decoded[extendedKeyLength:]) != 1 {
+		return nil, errors.New("cannot decode extended key because checksum is wrong")
+	}
+	return DeserializeExtendedKey(payload)
+}
+
+// Encode returns the base58 encoding (with checksum) of the extended key
+func (k *ExtendedKey) Encode() (string, error) {
+	serialized, err := k.Serialize()
+	if err != nil {
+		return "", err
+	}
+	return base58.CheckEncode(serialized[1:], serialized[0]), nil
+}
+
+// PublicKey returns the compressed public key of the extended key
+func (k *ExtendedKey) PublicKey() []byte {
+	if k.IsPrivate() {
+		return Public(k.Key, true)
+	}
+	return k.Key
+}
+
+// Fingerprint returns the first 4 bytes of the hash160 of the public key, which identifies the key as parent of its children
+func (k *ExtendedKey) Fingerprint() uint32 {
+	return binary.BigEndian.Uint32(Hashed(k.PublicKey())[:4])
+}
+
+// Neuter returns the public extended key of the same script type and network of a private extended key
+func (k *ExtendedKey) Neuter() (*ExtendedKey, error) {
+	if !k.IsPrivate() {
+		return k, nil
+	}
+	version, err := counterpartVersion(k.Version, false)
+	if err != nil {
+		return nil, err
+	}
+	return &ExtendedKey{
+		Version:           version,
+		Depth:             k.Depth,
+		ParentFingerprint: k.ParentFingerprint,
+		ChildNumber:       k.ChildNumber,
+		ChainCode:         k.ChainCode,
+		Key:               k.PublicKey(),
+	}, nil
+}
+
+// Child derives the child extended key at index (BIP32 CKDpriv for private keys, CKDpub for public keys).
+// Hardened children (index >= HardenedOffset) can only be derived from private keys
+func (k *ExtendedKey) Child(index uint32) (*ExtendedKey, error) {
+	if k.Depth == 255 {
+		return nil, errors.New("cannot derive beyond depth 255")
+	}
+	if len(k.ChainCode) != 32 {
+		return nil, fmt.Errorf("chain code is %d bytes long, must be 32", len(k.ChainCode))
+	}
+	hardened := index >= HardenedOffset
+	if hardened && !k.IsPrivate() {
+		return nil, errors.New("cannot derive a hardened child from a public key")
+	}
+	data := make([]byte, 37)
+	if hardened {
+		copy(data[1:33], k.Key)
+	} else {
+		copy(data[:33], k.PublicKey())
+	}
+	binary.BigEndian.PutUint32(data[33:], index)
+	mac := hmac.New(sha512.New, k.ChainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+	curve := btcec.S256()
+	il := new(big.Int).SetBytes(sum[:32])
+	if il.Cmp(curve.N) >= 0 {
+		return nil, fmt.Errorf("child %d is invalid, derive the next index", index)
+	}
+	child := &ExtendedKey{
+		Version:           k.Version,
+		Depth:             k.Depth + 1,
+		ParentFingerprint: k.Fingerprint(),
+		ChildNumber:       index,
+		ChainCode:         sum[32:],
+	}
+	if k.IsPrivate() {
+		il.Add(il, new(big.Int).SetBytes(k.Key)).Mod(il, curve.N)
+		if il.Sign() == 0 {
+			return nil, fmt.Errorf("child %d is invalid, derive the next index", index)
+		}
+		child.Key = il.FillBytes(make([]byte, 32))
+		return child, nil
+	}
+	parent, err := btcec.ParsePubKey(k.Key, curve)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse public key due to %v", err)
+	}
+	ilx, ily := curve.ScalarBaseMult(sum[:32])
+	x, y := curve.Add(ilx, ily, parent.X, parent.Y)
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return nil, fmt.Errorf("child %d is invalid, derive the next index", index)
+	}
+	child.Key = (&btcec.PublicKey{Curve: curve, X: x, Y: y}).SerializeCompressed()
+	return child, nil
+}
+
+// MasterFingerprintFromMnemonic returns the 4 bytes fingerprint of the master key of a BIP39 mnemonic and passphrase,
+// the one written at the start of key origins in descriptors, e.g. [73c5da0a/84'/0'/0']. A different passphrase gives a different wallet,
+// so a fingerprint that does not match the descriptor usually means a wrong or forgotten passphrase
+func MasterFingerprintFromMnemonic(mnemonic, passphrase string) ([]byte, error) {
+	wordListMutex.RLock()
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	wordListMutex.RUnlock()
+	if err != nil {
+		return nil, fmt.Errorf("invalid mnemonic due to %v", err)
+	}
+	master, err := masterKeyFromSeed(seed)
+	if err != nil {
+		return nil, err
+	}
+	fingerprint := make([]byte, 4)
+	binary.BigEndian.PutUint32(fingerprint, master.Fingerprint())
+	return fingerprint, nil
+}
+
+// MasterKey returns the BIP32 master private key and chain code of a 16 to 64 bytes seed, such as the one returned by FromMnemonic
+func MasterKey(seed []byte) (privKey []byte, chainCode []byte, err error) {
+	master, err := masterKeyFromSeed(seed)
+	if err != nil {
+		return nil, nil, err
+	}
+	return master.Key, master.ChainCode, nil
+}
+
+// DeriveChild returns the private key and chain code of the child at index of a private key and chain code (BIP32 CKDpriv).
+// Hardened children (index >= HardenedOffset) hash the parent private key, normal children its compressed public key
+func DeriveChild(privKey []byte, chainCode []byte, index uint32) (childPriv []byte, childChain []byte, err error) {
+	if len(privKey) != 32 || !isValidKey(new(big.Int).SetBytes(privKey)) {
+		return nil, nil, errors.New("given key is not acceptable as private key")
+	}
+	parent := &ExtendedKey{Version: 0x0488ADE4, ChainCode: chainCode, Key: privKey}
+	child, err := parent.Child(index)
+	if err != nil {
+		return nil, nil, err
+	}
+	return child.Key, child.ChainCode, nil
+}
+
+// SerializeExtendedPrivate returns the base58 extended private key (xprv, tprv on testnet) of a private key and chain code
+// at the given depth, parent fingerprint and child number, the format wallets exchange BIP32 keys in
+func SerializeExtendedPrivate(privKey, chainCode []byte, depth byte, parentFingerprint uint32, childNumber uint32, testnet bool) (string, error) {
+	if len(privKey) != 32 || !isValidKey(new(big.Int).SetBytes(privKey)) {
+		return "", errors.New("given key is not acceptable as private key")
+	}
+	return serializeExtended(privKey, chainCode, depth, parentFingerprint, childNumber, testnet, true)
+}
+
+// SerializeExtendedPublic returns the base58 extended public key (xpub, tpub on testnet) of a 33 bytes compressed public key and chain code
+// at the given depth, parent fingerprint and child number
+func SerializeExtendedPublic(pubKey, chainCode []byte, depth byte, parentFingerprint uint32, childNumber uint32, testnet bool) (string, error) {
+	if _, err := btcec.ParsePubKey(pubKey, btcec.S256()); err != nil {
+		return "", fmt.Errorf("cannot parse public key due to %v", err)
+	}
+	return serializeExtended(pubKey, chainCode, depth, parentFingerprint, childNumber, testnet, false)
+}
+
+func serializeExtended(key, chainCode []byte, depth byte, parentFingerprint uint32, childNumber uint32, testnet bool, private bool) (string, error) {
+	network := MainNet
+	if testnet {
+		network = TestNet
+	}
+	version, err := versionFor(ScriptTypeP2PKH, network, private)
+	if err != nil {
+		return "", err
+	}
+	extended := &ExtendedKey{
+		Version:           version,
+		Depth:             depth,
+		ParentFingerprint: parentFingerprint,
+		ChildNumber:       childNumber,
+		ChainCode:         chainCode,
+		Key:               key,
+	}
+	return extended.Encode()
+}
+
+// masterKeyFromSeed returns the BIP32 master extended private key (xprv) of a 16 to 64 bytes seed:
+// the HMAC-SHA512 of the seed with key "Bitcoin seed" gives the private key (left half) and the chain code (right half)
+func masterKeyFromSeed(seed []byte) (*ExtendedKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, fmt.Errorf("seed is %d bytes long, must be between 16 and 64", len(seed))
+	}
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	if !isValidKey(new(big.Int).SetBytes(sum[:32])) {
+		return nil, errors.New("seed gives an invalid master key, use another seed")
+	}
+	return &ExtendedKey{Version: 0x0488ADE4, ChainCode: sum[32:], Key: sum[:32]}, nil
+}
+
+// DeriveHardenedPath derives a private key along a path of hardened indexes only (each one >= HardenedOffset).
+// A leaked extended public key together with any non-hardened child private key reveals the parent private key, and so
+// all the siblings: hardened children are immune to that. The trade-off is that they cannot be derived from an extended public key,
+// so a watch-only wallet cannot generate their addresses
+func DeriveHardenedPath(master *ExtendedKey, indices []uint32) (*ExtendedKey, error) {
+	if !master.IsPrivate() {
+		return nil, errors.New("hardened keys can only be derived from a private key")
+	}
+	for i, index := range indices {
+		if index < HardenedOffset {
+			return nil, fmt.Errorf("index %d at position %d is not hardened", index, i)
+		}
+	}
+	key := master
+	var err error
+	for _, index := range indices {
+		if key, err = key.Child(index); err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// AccountXpub derives the account extended public key m/purpose'/coin'/account' of a master private key and returns it
+// with its key origin, e.g. [d34db33f/84'/0'/0']zpub..., the form watch-only wallets (Sparrow, Electrum) import.
+// The version follows the purpose: ypub for 49, zpub for 84, xpub otherwise (tpub, upub, vpub on testnet)
+func AccountXpub(master *ExtendedKey, purpose, coin, account uint32, network Network) (string, error) {
+	if master.Depth != 0 || !master.IsPrivate() {
+		return "", errors.New("account extended key must be derived from a master private key")
+	}
+	for _, index := range []uint32{purpose, coin, account} {
+		if index >= HardenedOffset {
+			return "", fmt.Errorf("index %d is too big, give it without the hardened offset", index)
+		}
+	}
+	scriptType := ScriptTypeP2PKH
+	switch purpose {
+	case 49:
+		scriptType = ScriptTypeP2SHP2WPKH
+	case 84:
+		scriptType = ScriptTypeP2WPKH
+	}
+	version, err := versionFor(scriptType, network, false)
+	if err != nil {
+		return "", err
+	}
+	key := master
+	for _, index := range []uint32{purpose, coin, account} {
+		if key, err = key.Child(index + HardenedOffset); err != nil {
+			return "", fmt.Errorf("cannot derive account key due to %v", err)
+		}
+	}
+	if key, err = key.Neuter(); err != nil {
+		return "", err
+	}
+	key.Version = version
+	encoded, err := key.Encode()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("[%08x/%d'/%d'/%d']%v", master.Fingerprint(), purpose, coin, account, encoded), nil
+}
+
+// counterpartVersion returns the private or public version with the same script type and network of a version
+func counterpartVersion(version uint32, private bool) (uint32, error) {
+	v, ok := extendedVersions[version]
+	if !ok {
+		return 0, fmt.Errorf("unknown extended key version %x", version)
+	}
+	return versionFor(v.scriptType, v.network, private)
+}
+
+// versionFor returns the extended key version of a script type on a network
+func versionFor(scriptType string, network Network, private bool) (uint32, error) {
+	for version, v := range extendedVersions {
+		if v.scriptType == scriptType && v.network == network && v.private == private {
+			return version, nil
+		}
+	}
+	return 0, fmt.Errorf("no extended key version for %v on %v", scriptType, network)
+}
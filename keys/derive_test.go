@@ -0,0 +1,253 @@
+package keys
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"testing"
+
+	bip39 "github.com/tyler-smith/go-bip39"
+)
+
+const bip32Vector1Master = "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi"
+
+func TestChild(t *testing.T) {
+	// https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki#test-vector-1
+	master, err := ParseExtendedKey(bip32Vector1Master)
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	// m/0H
+	child, err := master.Child(HardenedOffset)
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	expected := "xprv9uHRZZhk6KAJC1avXpDAp4MDc3sQKNxDiPvvkX8Br5ngLNv1TxvUxt4cV1rGL5hj6KCesnDYUhd7oWgT11eZG7XnxHrnYeSvkzY7d2bhkJ7"
+	if s, _ := child.Encode(); s != expected {
+		t.Errorf("m/0H should be %v but is %v", expected, s)
+	}
+	public, err := child.Neuter()
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	expected = "xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw"
+	if s, _ := public.Encode(); s != expected {
+		t.Errorf("m/0H public should be %v but is %v", expected, s)
+	}
+	// m/0H/1 from the private and from the public parent
+	expected = "xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ"
+	fromPrivate, err := child.Child(1)
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	fromPrivate, _ = fromPrivate.Neuter()
+	if s, _ := fromPrivate.Encode(); s != expected {
+		t.Errorf("m/0H/1 derived from private should be %v but is %v", expected, s)
+	}
+	fromPublic, err := public.Child(1)
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	if s, _ := fromPublic.Encode(); s != expected {
+		t.Errorf("m/0H/1 derived from public should be %v but is %v", expected, s)
+	}
+	if _, err := public.Child(HardenedOffset); err == nil {
+		t.Errorf("hardened child of a public key should return an error")
+	}
+}
+
+func TestParseExtendedKeyErrors(t *testing.T) {
+	wrong := []string{
+		"",
+		"xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHj",
+		"xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPH",
+	}
+	for _, w := range wrong {
+		if _, err := ParseExtendedKey(w); err == nil {
+			t.Errorf("extended key %v should not be accepted", w)
+		}
+	}
+	// the checksum error must not echo a private key into logs
+	_, err := ParseExtendedKey(wrong[1])
+	if err == nil || strings.Contains(err.Error(), wrong[1][:20]) {
+		t.Errorf("checksum error should not contain the key, got %v", err)
+	}
+}
+
+func TestAccountXpub(t *testing.T) {
+	master, _ := ParseExtendedKey(bip32Vector1Master)
+	expected := []struct {
+		purpose, coin, account uint32
+		network                Network
+		xpub                   string
+	}{
+		{44, 0, 0, MainNet, "[3442193e/44'/0'/0']xpub6CDEarkRoiwWPj3n3gYygGwgoGchxYg3g6Zs5L2nB4B6wdojzcWCKKHMu9XuY1GyYygRfrVembjAko1T5xTsxj7ecKXxEPzDxx7nCK8Dxtx"},
+		{49, 0, 0, MainNet, "[3442193e/49'/0'/0']ypub6X72NFZXyacDVkCZu4gNxmiPFJkqaKe5etAB1DDo6mtoEm9FugxbgpGAPFxNLvCmuNs7YpnA69YVo7iEGPnX1HSL6y5gtVFcxZFHRqGZsPs"},
+		{84, 0, 0, MainNet, "[3442193e/84'/0'/0']zpub6qfp6hKyMTw1jdnUQGr4xihYxp7rQmAPp67pk4YYAcZBdRisqyaqh1Z2N1RVCNtEVW6c4eLuPZctjUx3QVBQEQPFNaR5uvumrzUbGRQ8voQ"},
+		{86, 0, 0, MainNet, "[3442193e/86'/0'/0']xpub6DRX1xNPHKaApgDnqaMNxJ8Lz35KCn3mRcW3LUep3JKhxWisRwaZJPn4BuZiaJ4kJ3cdqwbn4vZcsGiLGJJabZbqa65LGX2uhU9CtPWSgEn"},
+		{44, 1, 0, TestNet, "[3442193e/44'/1'/0']tpubDDW4jVEAkwNoHumzePCtQ5FcxXVc8RG8ACszXP1HD1WThkZ19sAoyaNeiXswjTtAKM14zjo8rdhxadti7zuNSfJBMuG68oxQ3Bi1wgo88fD"},
+	}
+	for _, v := range expected {
+		xpub, err := AccountXpub(master, v.purpose, v.coin, v.account, v.network)
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+			continue
+		}
+		if xpub != v.xpub {
+			t.Errorf("account %d'/%d'/%d' should be %v but is %v", v.purpose, v.coin, v.account, v.xpub, xpub)
+		}
+	}
+	if _, err := AccountXpub(master, HardenedOffset+44, 0, 0, MainNet); err == nil {
+		t.Errorf("hardened purpose should return an error")
+	}
+	child, _ := master.Child(HardenedOffset)
+	if _, err := AccountXpub(child, 44, 0, 0, MainNet); err == nil {
+		t.Errorf("non master key should return an error")
+	}
+	public, _ := master.Neuter()
+	if _, err := AccountXpub(public, 44, 0, 0, MainNet); err == nil {
+		t.Errorf("public master key should return an error")
+	}
+}
+
+func TestDeriveHardenedPath(t *testing.T) {
+	// https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki#test-vector-1
+	master, _ := ParseExtendedKey(bip32Vector1Master)
+	key, err := DeriveHardenedPath(master, []uint32{HardenedOffset})
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	expected := "xprv9uHRZZhk6KAJC1avXpDAp4MDc3sQKNxDiPvvkX8Br5ngLNv1TxvUxt4cV1rGL5hj6KCesnDYUhd7oWgT11eZG7XnxHrnYeSvkzY7d2bhkJ7"
+	if s, _ := key.Encode(); s != expected {
+		t.Errorf("m/0H should be %v but is %v", expected, s)
+	}
+	if key, _ := DeriveHardenedPath(master, nil); key != master {
+		t.Errorf("empty path should return the master key")
+	}
+	if _, err := DeriveHardenedPath(master, []uint32{HardenedOffset, 1}); err == nil {
+		t.Errorf("non hardened index should return an error")
+	}
+	public, _ := master.Neuter()
+	if _, err := DeriveHardenedPath(public, []uint32{HardenedOffset}); err == nil {
+		t.Errorf("public key should return an error")
+	}
+}
+
+func TestMasterFingerprintFromMnemonic(t *testing.T) {
+	// https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki#test-vector-1, master fingerprint 3442193e
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	master, err := masterKeyFromSeed(seed)
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	if s, _ := master.Encode(); s != bip32Vector1Master {
+		t.Errorf("master key should be %v but is %v", bip32Vector1Master, s)
+	}
+	if master.Fingerprint() != 0x3442193e {
+		t.Errorf("master fingerprint should be 3442193e but is %08x", master.Fingerprint())
+	}
+	mnemonic, _ := Mnemonic(seed)
+	fingerprint, err := MasterFingerprintFromMnemonic(mnemonic, "")
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	expected, _ := masterKeyFromSeed(bip39.NewSeed(mnemonic, ""))
+	if binary.BigEndian.Uint32(fingerprint) != expected.Fingerprint() {
+		t.Errorf("fingerprint should be %08x but is %x", expected.Fingerprint(), fingerprint)
+	}
+	withPassphrase, err := MasterFingerprintFromMnemonic(mnemonic, "TREZOR")
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	if bytes.Equal(fingerprint, withPassphrase) {
+		t.Errorf("a passphrase should change the fingerprint")
+	}
+	if _, err := MasterFingerprintFromMnemonic("not a mnemonic", ""); err == nil {
+		t.Errorf("invalid mnemonic should return an error")
+	}
+	if _, err := masterKeyFromSeed(seed[:15]); err == nil {
+		t.Errorf("15 bytes seed should return an error")
+	}
+}
+
+func TestMasterKey(t *testing.T) {
+	// https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki#test-vector-1
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	privKey, chainCode, err := MasterKey(seed)
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	if hex.EncodeToString(privKey) != "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35" {
+		t.Errorf("master private key should be e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35 but is %x", privKey)
+	}
+	if hex.EncodeToString(chainCode) != "873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508" {
+		t.Errorf("master chain code should be 873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508 but is %x", chainCode)
+	}
+	if _, _, err := MasterKey(make([]byte, 65)); err == nil {
+		t.Errorf("65 bytes seed should return an error")
+	}
+}
+
+func TestDeriveChild(t *testing.T) {
+	// https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki#test-vector-1, m/0H then m/0H/1
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	privKey, chainCode, _ := MasterKey(seed)
+	// Index, child private key, child chain code
+	expected := [][]string{
+		[]string{"2147483648", "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea", "47fdacbd0f1097043b78c63c20c34ef4ed9a111d980047ad16282c7ae6236141"},
+		[]string{"1", "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368", "2a7857631386ba23dacac34180dd1983734e444fdbf774041578e9b6adb37c19"},
+	}
+	for _, v := range expected {
+		index, _ := strconv.ParseUint(v[0], 10, 32)
+		var err error
+		privKey, chainCode, err = DeriveChild(privKey, chainCode, uint32(index))
+		if err != nil {
+			t.Fatalf("failed due to %v\n", err)
+		}
+		if hex.EncodeToString(privKey) != v[1] || hex.EncodeToString(chainCode) != v[2] {
+			t.Errorf("child %v should be %v %v but is %x %x", v[0], v[1], v[2], privKey, chainCode)
+		}
+	}
+	if _, _, err := DeriveChild(privKey, chainCode[:31], 0); err == nil {
+		t.Errorf("short chain code should return an error")
+	}
+	if _, _, err := DeriveChild(make([]byte, 32), chainCode, 0); err == nil {
+		t.Errorf("zero key should return an error")
+	}
+}
+
+func TestSerializeExtended(t *testing.T) {
+	// https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki#test-vector-1, m and m/0H
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	privKey, chainCode, _ := MasterKey(seed)
+	xprv, err := SerializeExtendedPrivate(privKey, chainCode, 0, 0, 0, false)
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	if xprv != bip32Vector1Master {
+		t.Errorf("xprv should be %v but is %v", bip32Vector1Master, xprv)
+	}
+	xpub, err := SerializeExtendedPublic(Public(privKey, true), chainCode, 0, 0, 0, false)
+	expected := "xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8"
+	if err != nil || xpub != expected {
+		t.Errorf("xpub should be %v but is %v (%v)", expected, xpub, err)
+	}
+	childPriv, childChain, _ := DeriveChild(privKey, chainCode, HardenedOffset)
+	xprv, err = SerializeExtendedPrivate(childPriv, childChain, 1, 0x3442193e, HardenedOffset, false)
+	expected = "xprv9uHRZZhk6KAJC1avXpDAp4MDc3sQKNxDiPvvkX8Br5ngLNv1TxvUxt4cV1rGL5hj6KCesnDYUhd7oWgT11eZG7XnxHrnYeSvkzY7d2bhkJ7"
+	if err != nil || xprv != expected {
+		t.Errorf("xprv should be %v but is %v (%v)", expected, xprv, err)
+	}
+	tprv, err := SerializeExtendedPrivate(privKey, chainCode, 0, 0, 0, true)
+	if err != nil || tprv[:4] != "tprv" {
+		t.Errorf("testnet key should start with tprv, got %v (%v)", tprv, err)
+	}
+	if _, err := SerializeExtendedPublic(Public(privKey, false), chainCode, 0, 0, 0, false); err == nil {
+		t.Errorf("uncompressed public key should return an error")
+	}
+	if _, err := SerializeExtendedPrivate(privKey, chainCode[:31], 0, 0, 0, false); err == nil {
+		t.Errorf("short chain code should return an error")
+	}
+}
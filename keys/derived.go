@@ -0,0 +1,77 @@
+package keys
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec"
+	"golang.org/x/crypto/hkdf"
+)
+
+// TOTPSecret derives a 20 bytes secret for TOTP authenticator apps (RFC 6238) as HMAC-SHA1(privKey, label) and returns it base32 encoded.
+// The same key and label always give the same secret, so the 2FA can be restored from the backup of the key.
+// The secret is only as safe as the private key: anyone holding the key can regenerate it, and it must not be used to protect the key itself
+func TOTPSecret(privKey []byte, label string) (base32Secret string, err error) {
+	if len(privKey) != 32 || !isValidKey(new(big.Int).SetBytes(privKey)) {
+		return "", errors.New("input represents a number not acceptable as private key")
+	}
+	if len(label) == 0 {
+		return "", fmt.Errorf("label cannot be empty")
+	}
+	mac := hmac.New(sha1.New, privKey)
+	mac.Write([]byte(label))
+	secret := mac.Sum(nil)
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(secret), nil
+}
+
+// fileEncryptionSalt is the HKDF salt of file encryption keys, which separates them from keys derived for any other use
+const fileEncryptionSalt = "cashline file encryption"
+
+// FileEncryptionKey derives a 32 bytes AES-256 key for file encryption as HKDF-SHA256(privKey, salt, context) (RFC 5869).
+// The private key is never used directly as an encryption key: HKDF gives an independent key for every context
+// (e.g. "photos", "documents"), so that a key leaked from one context reveals neither the others nor the signing key
+func FileEncryptionKey(privKey []byte, context string) ([]byte, error) {
+	if len(privKey) != 32 || !isValidKey(new(big.Int).SetBytes(privKey)) {
+		return nil, errors.New("input represents a number not acceptable as private key")
+	}
+	if len(context) == 0 {
+		return nil, fmt.Errorf("context cannot be empty")
+	}
+	return hkdfSHA256(privKey, []byte(fileEncryptionSalt), []byte(context), 32)
+}
+
+// hkdfSHA256 returns length bytes of HKDF-SHA256 (RFC 5869) of secret, salt and info
+func hkdfSHA256(secret, salt, info []byte, length int) ([]byte, error) {
+	key := make([]byte, length)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, info), key); err != nil {
+		return nil, fmt.Errorf("cannot derive key due to %v", err)
+	}
+	return key, nil
+}
+
+// DeriveOneTimeKey derives the private key number counter of a base key as HMAC-SHA512(baseKey, counter) reduced modulo the curve order,
+// for a fresh key per payment without HD wallets. The derived keys are unrelated on chain, but they are linked to the base key:
+// it is needed (with the counters used) to recover them, and anyone holding it can derive them all
+func DeriveOneTimeKey(baseKey []byte, counter uint64) ([]byte, error) {
+	if len(baseKey) != 32 || !isValidKey(new(big.Int).SetBytes(baseKey)) {
+		return nil, errors.New("input represents a number not acceptable as private key")
+	}
+	message := make([]byte, 8)
+	binary.BigEndian.PutUint64(message, counter)
+	mac := hmac.New(sha512.New, baseKey)
+	mac.Write(message)
+	bi := new(big.Int).SetBytes(mac.Sum(nil))
+	bi.Mod(bi, btcec.S256().N)
+	if !isValidKey(bi) {
+		return nil, fmt.Errorf("counter %d gives an invalid key, use the next one", counter)
+	}
+	return bi.FillBytes(make([]byte, 32)), nil
+}
@@ -0,0 +1,122 @@
+package keys
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestTOTPSecret(t *testing.T) {
+	privKeyHexString := "0C28FCA386C7A227600B2FE50B7CAE11EC86D3BF1FBE471BE89827E19D72AA1D"
+	privKeyByte, err := hex.DecodeString(privKeyHexString)
+	if err != nil {
+		t.Errorf("Cannot decode private key")
+	}
+	// Label, base32 secret
+	expected := [][]string{
+		[]string{"github.com", "GUNO42J44U7GITPVA4Z4H24HBCBC7ERX"},
+		[]string{"mail", "IXO2RVEWMF7FD4ZJUAUTLSEBOBN3IUTN"},
+	}
+	for _, v := range expected {
+		secret, err := TOTPSecret(privKeyByte, v[0])
+		if err != nil {
+			t.Errorf("cannot derive secret due to %v", err)
+		}
+		if secret != v[1] {
+			t.Errorf("secret for label %v should be %v but is %v", v[0], v[1], secret)
+		}
+	}
+	if _, err := TOTPSecret(privKeyByte, ""); err == nil {
+		t.Errorf("empty label should return an error")
+	}
+	if _, err := TOTPSecret(make([]byte, 32), "mail"); err == nil {
+		t.Errorf("zero key should return an error")
+	}
+	// a key with a leading zero byte dropped or added would give a different secret
+	if _, err := TOTPSecret(privKeyByte[1:], "mail"); err == nil {
+		t.Errorf("31 bytes key should return an error")
+	}
+	if _, err := TOTPSecret(append([]byte{0x00}, privKeyByte...), "mail"); err == nil {
+		t.Errorf("33 bytes key should return an error")
+	}
+}
+
+func TestFileEncryptionKey(t *testing.T) {
+	privKeyByte, _ := hex.DecodeString("0C28FCA386C7A227600B2FE50B7CAE11EC86D3BF1FBE471BE89827E19D72AA1D")
+	// Context, hex key
+	expected := [][]string{
+		[]string{"photos", "75130b5c2cf80f0c365684ef4ae8cba2430a454fcf6e84ed1059b52613a85026"},
+		[]string{"documents", "f5f814b0f75de9d259546f4eb92bd4a805b3d7289d2ef82315e6d9d573b54017"},
+	}
+	for _, v := range expected {
+		key, err := FileEncryptionKey(privKeyByte, v[0])
+		if err != nil {
+			t.Errorf("cannot derive key due to %v", err)
+		}
+		if hex.EncodeToString(key) != v[1] {
+			t.Errorf("key for context %v should be %v but is %x", v[0], v[1], key)
+		}
+	}
+	if _, err := FileEncryptionKey(privKeyByte, ""); err == nil {
+		t.Errorf("empty context should return an error")
+	}
+	if _, err := FileEncryptionKey(make([]byte, 32), "photos"); err == nil {
+		t.Errorf("zero key should return an error")
+	}
+	if _, err := FileEncryptionKey(privKeyByte[1:], "photos"); err == nil {
+		t.Errorf("31 bytes key should return an error")
+	}
+	if _, err := FileEncryptionKey(append([]byte{0x00}, privKeyByte...), "photos"); err == nil {
+		t.Errorf("33 bytes key should return an error")
+	}
+}
+
+func TestHKDFSHA256(t *testing.T) {
+	// https://www.rfc-editor.org/rfc/rfc5869#appendix-A, test cases 1 and 3
+	// IKM, salt, info, OKM
+	expected := [][]string{
+		[]string{"0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b", "000102030405060708090a0b0c", "f0f1f2f3f4f5f6f7f8f9",
+			"3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865"},
+		[]string{"0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b", "", "",
+			"8da4e775a563c18f715f802a063c5a31b8a11f5c5ee1879ec3454e5f3c738d2d9d201395faa4b61a96c8"},
+	}
+	for _, v := range expected {
+		ikm, _ := hex.DecodeString(v[0])
+		salt, _ := hex.DecodeString(v[1])
+		info, _ := hex.DecodeString(v[2])
+		okm, err := hkdfSHA256(ikm, salt, info, len(v[3])/2)
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+			continue
+		}
+		if hex.EncodeToString(okm) != v[3] {
+			t.Errorf("okm should be %v but is %x", v[3], okm)
+		}
+	}
+}
+
+func TestDeriveOneTimeKey(t *testing.T) {
+	baseKey, _ := hex.DecodeString("0C28FCA386C7A227600B2FE50B7CAE11EC86D3BF1FBE471BE89827E19D72AA1D")
+	expected := []struct {
+		counter uint64
+		key     string
+	}{
+		{0, "92527bf3080d24e59bff759999c5655ded29787c2eed4e80b70b7249f7ead0b1"},
+		{1, "0eb8028e230c9561da08b0ad7b99bcbd1965c74e03f6897bf5e8d3f4baeadcaa"},
+		{1 << 40, "17cfe39c1c78b957cdc97a797ad0c769286381b89c0f46686b3e61ebda6d624f"},
+	}
+	for _, v := range expected {
+		key, err := DeriveOneTimeKey(baseKey, v.counter)
+		if err != nil {
+			t.Errorf("cannot derive key due to %v", err)
+		}
+		if hex.EncodeToString(key) != v.key {
+			t.Errorf("key for counter %d should be %v but is %x", v.counter, v.key, key)
+		}
+	}
+	if _, err := DeriveOneTimeKey(make([]byte, 32), 0); err == nil {
+		t.Errorf("zero key should return an error")
+	}
+	if _, err := DeriveOneTimeKey(baseKey[1:], 0); err == nil {
+		t.Errorf("short key should return an error")
+	}
+}
@@ -0,0 +1,163 @@
+package keys
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec"
+)
+
+// descriptorInputCharset and descriptorChecksumCharset are defined by BIP380
+const descriptorInputCharset = "0123456789()[],'/*abcdefgh@:$%{}IJKLMNOPQRSTUVWXYZ&+-.;<=>?!^_|~ijklmnopqrstuvwxyzABCDEFGH`#\"\\ "
+const descriptorChecksumCharset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// ValidateDescriptor checks the checksum and the key expression of a single key output descriptor (pkh, wpkh, sh(wpkh) or key path only tr)
+// and returns the script type it describes, with the network of its extended key. Descriptors with a bare public key do not
+// tell the network and are reported as MainNet.
+// Reference: https://github.com/bitcoin/bips/blob/master/bip-0380.mediawiki
+func ValidateDescriptor(descriptor string) (scriptType string, network Network, err error) {
+	hash := strings.LastIndexByte(descriptor, '#')
+	if hash < 0 {
+		return "", MainNet, errors.New("descriptor has no checksum")
+	}
+	body, checksum := descriptor[:hash], descriptor[hash+1:]
+	expected, err := descriptorChecksum(body)
+	if err != nil {
+		return "", MainNet, err
+	}
+	if checksum != expected {
+		return "", MainNet, fmt.Errorf("descriptor checksum is %v, expected %v", checksum, expected)
+	}
+	var key string
+	switch {
+	case strings.HasPrefix(body, "sh(wpkh(") && strings.HasSuffix(body, "))"):
+		scriptType, key = ScriptTypeP2SHP2WPKH, body[len("sh(wpkh("):len(body)-2]
+	case strings.HasPrefix(body, "pkh(") && strings.HasSuffix(body, ")"):
+		scriptType, key = ScriptTypeP2PKH, body[len("pkh("):len(body)-1]
+	case strings.HasPrefix(body, "wpkh(") && strings.HasSuffix(body, ")"):
+		scriptType, key = ScriptTypeP2WPKH, body[len("wpkh("):len(body)-1]
+	case strings.HasPrefix(body, "tr(") && strings.HasSuffix(body, ")"):
+		scriptType, key = ScriptTypeP2TR, body[len("tr("):len(body)-1]
+		if strings.ContainsRune(key, ',') {
+			return "", MainNet, errors.New("taproot descriptors with a script tree are not supported")
+		}
+	default:
+		return "", MainNet, fmt.Errorf("unsupported descriptor %v", body)
+	}
+	network, err = validateKeyExpression(key, scriptType)
+	if err != nil {
+		return "", MainNet, err
+	}
+	return scriptType, network, nil
+}
+
+// validateKeyExpression checks a key expression: an optional [fingerprint/path] origin followed by a hex public key
+// or by an extended key with an optional derivation path ending with * or *' (and <a;b> multipath steps)
+func validateKeyExpression(key string, scriptType string) (Network, error) {
+	if strings.HasPrefix(key, "[") {
+		end := strings.IndexByte(key, ']')
+		if end < 0 {
+			return MainNet, errors.New("key origin is not closed")
+		}
+		origin := strings.Split(key[1:end], "/")
+		if len(origin[0]) != 8 {
+			return MainNet, fmt.Errorf("key origin fingerprint %v must be 8 hex chars", origin[0])
+		}
+		if _, err := hex.DecodeString(origin[0]); err != nil {
+			return MainNet, fmt.Errorf("key origin fingerprint %v is not hex", origin[0])
+		}
+		for _, step := range origin[1:] {
+			if !validPathStep(step) {
+				return MainNet, fmt.Errorf("invalid key origin path step %v", step)
+			}
+		}
+		key = key[end+1:]
+	}
+	if raw, err := hex.DecodeString(key); err == nil {
+		switch {
+		case scriptType == ScriptTypeP2TR && len(raw) == 32:
+			raw = append([]byte{0x02}, raw...)
+		case scriptType == ScriptTypeP2PKH && len(raw) == 65:
+		case len(raw) == 33:
+		default:
+			return MainNet, fmt.Errorf("public key of %d bytes not allowed in %v descriptor", len(raw), scriptType)
+		}
+		if _, err := btcec.ParsePubKey(raw, btcec.S256()); err != nil {
+			return MainNet, fmt.Errorf("cannot parse public key due to %v", err)
+		}
+		return MainNet, nil
+	}
+	steps := strings.Split(key, "/")
+	extended, err := ParseExtendedKey(steps[0])
+	if err != nil {
+		return MainNet, err
+	}
+	for i, step := range steps[1:] {
+		last := i == len(steps)-2
+		switch {
+		case last && (step == "*" || step == "*'" || step == "*h"):
+		case strings.HasPrefix(step, "<") && strings.HasSuffix(step, ">"):
+			for _, alternative := range strings.Split(step[1:len(step)-1], ";") {
+				if !validPathStep(alternative) {
+					return MainNet, fmt.Errorf("invalid multipath step %v", step)
+				}
+			}
+		case !validPathStep(step):
+			return MainNet, fmt.Errorf("invalid derivation path step %v", step)
+		}
+	}
+	return extendedVersions[extended.Version].network, nil
+}
+
+// validPathStep tells if a derivation path step is an index below 2^31, optionally hardened with ' or h
+func validPathStep(step string) bool {
+	step = strings.TrimRight(step, "'h")
+	index, err := strconv.ParseUint(step, 10, 32)
+	return err == nil && uint32(index) < HardenedOffset
+}
+
+// descriptorChecksum returns the 8 chars BIP380 checksum of a descriptor
+func descriptorChecksum(descriptor string) (string, error) {
+	c := uint64(1)
+	class, classCount := 0, 0
+	for i := 0; i < len(descriptor); i++ {
+		position := strings.IndexByte(descriptorInputCharset, descriptor[i])
+		if position < 0 {
+			return "", fmt.Errorf("char %c at position %d is not allowed in a descriptor", descriptor[i], i)
+		}
+		c = descriptorPolyMod(c, position&31)
+		class = class*3 + position>>5
+		classCount++
+		if classCount == 3 {
+			c = descriptorPolyMod(c, class)
+			class, classCount = 0, 0
+		}
+	}
+	if classCount > 0 {
+		c = descriptorPolyMod(c, class)
+	}
+	for i := 0; i < 8; i++ {
+		c = descriptorPolyMod(c, 0)
+	}
+	c ^= 1
+	checksum := make([]byte, 8)
+	for i := range checksum {
+		checksum[i] = descriptorChecksumCharset[(c>>(5*(7-uint(i))))&31]
+	}
+	return string(checksum), nil
+}
+
+func descriptorPolyMod(c uint64, value int) uint64 {
+	generators := [5]uint64{0xf5dee51989, 0xa9fdca3312, 0x1bab10e32d, 0x3706b1677a, 0x644d626ffd}
+	top := c >> 35
+	c = (c&0x7ffffffff)<<5 ^ uint64(value)
+	for i, g := range generators {
+		if (top>>uint(i))&1 == 1 {
+			c ^= g
+		}
+	}
+	return c
+}
@@ -0,0 +1,59 @@
+package keys
+
+import (
+	"testing"
+)
+
+func TestDescriptorChecksum(t *testing.T) {
+	// https://github.com/bitcoin/bips/blob/master/bip-0380.mediawiki#test-vectors
+	checksum, err := descriptorChecksum("raw(deadbeef)")
+	if err != nil {
+		t.Errorf("failed due to %v\n", err)
+	}
+	if checksum != "89f8spxm" {
+		t.Errorf("checksum should be 89f8spxm but is %v", checksum)
+	}
+}
+
+func TestValidateDescriptor(t *testing.T) {
+	expected := []struct {
+		descriptor string
+		scriptType string
+		network    Network
+	}{
+		{"pkh(02c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5)#8fhd9pwu", ScriptTypeP2PKH, MainNet},
+		{"pkh(0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8)#zvxck6mv", ScriptTypeP2PKH, MainNet},
+		{"wpkh([d34db33f/84'/0'/0']xpub6ERApfZwUNrhLCkDtcHTcxd75RbzS1ed54G1LkBUHQVHQKqhMkhgbmJbZRkrgZw4koxb5JaHWkY4ALHY2grBGRjaDMzQLcgJvLJuZZvRcEL/0/*)#yq904q8l", ScriptTypeP2WPKH, MainNet},
+		{"wpkh([3442193e/84h/0h/0h]xpub6DRX1xNPHKaApgDnqaMNxJ8Lz35KCn3mRcW3LUep3JKhxWisRwaZJPn4BuZiaJ4kJ3cdqwbn4vZcsGiLGJJabZbqa65LGX2uhU9CtPWSgEn/<0;1>/*)#gttsvlcv", ScriptTypeP2WPKH, MainNet},
+		{"wpkh(xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi/0h/*)#n53pcdgn", ScriptTypeP2WPKH, MainNet},
+		{"sh(wpkh(tpubD6NzVbkrYhZ4WaWSyoBvQwbpLkojyoTZPRsgXELWz3Popb3qkjcJyJUGLnL4qHHoQvao8ESaAstxYSnhyswJ76uZPStJRJCTKvosUCJZL5B/0/*))#hflv69f8", ScriptTypeP2SHP2WPKH, TestNet},
+		{"tr(a34b99f22c790c4e36b2b3c2c35a36db06226e41c692fc82b8b56ac1c540c5bd)#dh4fyxrd", ScriptTypeP2TR, MainNet},
+	}
+	for _, v := range expected {
+		scriptType, network, err := ValidateDescriptor(v.descriptor)
+		if err != nil {
+			t.Errorf("descriptor %v is not valid due to %v", v.descriptor, err)
+			continue
+		}
+		if scriptType != v.scriptType || network != v.network {
+			t.Errorf("descriptor %v should be %v on %v but is %v on %v", v.descriptor, v.scriptType, v.network, scriptType, network)
+		}
+	}
+	wrong := []string{
+		// missing and wrong checksum
+		"pkh(02c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5)",
+		"pkh(02c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5)#8fhd9pwv",
+		// unsupported function
+		"raw(deadbeef)#89f8spxm",
+		"sh(multi(1,022f8bde4d1a07209355b4a7250a5c5128e88b84bddc619ab7cba8d569b240efe4))#7scfqclu",
+		// uncompressed key in segwit
+		"wpkh(0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8)#fpeq8mq2",
+		// 7 chars fingerprint
+		"wpkh([d34db33/84'/0'/0']02c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5)#v6rtem93",
+	}
+	for _, w := range wrong {
+		if _, _, err := ValidateDescriptor(w); err == nil {
+			t.Errorf("descriptor %v should not be valid", w)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package keys
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec"
+)
+
+// sizes of the parts of an ECIES ciphertext
+const (
+	eciesPubKeySize = 33
+	eciesNonceSize  = 12
+	eciesTagSize    = 16
+	eciesMACSize    = sha256.Size
+)
+
+// EncryptToPublicKey encrypts a message that only the owner of the private key of pubKey (compressed or uncompressed) can decrypt.
+// A random ephemeral key is combined with pubKey by ECDH, the sha512 of the ephemeral public key and the x of the shared point
+// gives an AES-256-GCM key (first half) and an HMAC-SHA256 key (second half). The result is
+// ephemeral public key (33 bytes) || GCM nonce (12 bytes) || GCM ciphertext and tag || HMAC of all the previous bytes (32 bytes)
+func EncryptToPublicKey(pubKey []byte, plaintext []byte) ([]byte, error) {
+	curve := btcec.S256()
+	recipient, err := btcec.ParsePubKey(pubKey, curve)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse public key due to %v", err)
+	}
+	ephemeral, _, err := NewRandomKeyWithStats()
+	if err != nil {
+		return nil, err
+	}
+	// serialized by btcec, which pads x to 32 bytes
+	_, ephemeralKey := btcec.PrivKeyFromBytes(curve, ephemeral)
+	ephemeralPub := ephemeralKey.SerializeCompressed()
+	sharedX, _ := curve.ScalarMult(recipient.X, recipient.Y, ephemeral)
+	encKey, macKey := eciesKeys(ephemeralPub, sharedX)
+	gcm, err := eciesGCM(encKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, eciesNonceSize)
+	if _, err := io.ReadFull(randomSource, nonce); err != nil {
+		return nil, fmt.Errorf("cannot read random bytes due to %v", err)
+	}
+	ciphertext := append(ephemeralPub, nonce...)
+	ciphertext = gcm.Seal(ciphertext, nonce, plaintext, ephemeralPub)
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(ciphertext)
+	return mac.Sum(ciphertext), nil
+}
+
+// DecryptWithPrivateKey decrypts a message encrypted by EncryptToPublicKey for the public key of privKey,
+// returning an error if the ciphertext was altered or is for another key
+func DecryptWithPrivateKey(privKey []byte, ciphertext []byte) ([]byte, error) {
+	if len(privKey) != 32 || !isValidKey(new(big.Int).SetBytes(privKey)) {
+		return nil, errors.New("given key is not acceptable as private key")
+	}
+	if len(ciphertext) < eciesPubKeySize+eciesNonceSize+eciesTagSize+eciesMACSize {
+		return nil, fmt.Errorf("ciphertext is %d bytes long, too short to be encrypted by EncryptToPublicKey", len(ciphertext))
+	}
+	curve := btcec.S256()
+	ephemeralPub := ciphertext[:eciesPubKeySize]
+	ephemeral, err := btcec.ParsePubKey(ephemeralPub, curve)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse ephemeral public key due to %v", err)
+	}
+	sharedX, _ := curve.ScalarMult(ephemeral.X, ephemeral.Y, privKey)
+	encKey, macKey := eciesKeys(ephemeralPub, sharedX)
+	body := ciphertext[:len(ciphertext)-eciesMACSize]
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(body)
+	if !hmac.Equal(mac.Sum(nil), ciphertext[len(body):]) {
+		return nil, errors.New("ciphertext mac mismatch: wrong key or altered ciphertext")
+	}
+	gcm, err := eciesGCM(encKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := body[eciesPubKeySize : eciesPubKeySize+eciesNonceSize]
+	plaintext, err := gcm.Open(nil, nonce, body[eciesPubKeySize+eciesNonceSize:], ephemeralPub)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decrypt due to %v", err)
+	}
+	return plaintext, nil
+}
+
+// eciesKeys derives the encryption and the mac keys from the ephemeral public key and the x of the shared point
+func eciesKeys(ephemeralPub []byte, sharedX *big.Int) (encKey, macKey []byte) {
+	hash := sha512.New()
+	hash.Write(ephemeralPub)
+	hash.Write(sharedX.FillBytes(make([]byte, 32)))
+	keys := hash.Sum(nil)
+	return keys[:32], keys[32:]
+}
+
+func eciesGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create cipher due to %v", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create cipher due to %v", err)
+	}
+	return gcm, nil
+}
@@ -0,0 +1,61 @@
+package keys
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestEncryptToPublicKey(t *testing.T) {
+	privKey, _ := hex.DecodeString("0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d")
+	message := []byte("meet me at the usual place")
+	for _, compressed := range []bool{true, false} {
+		ciphertext, err := EncryptToPublicKey(Public(privKey, compressed), message)
+		if err != nil {
+			t.Fatalf("failed due to %v\n", err)
+		}
+		if len(ciphertext) != 33+12+len(message)+16+32 {
+			t.Errorf("unexpected ciphertext length %d", len(ciphertext))
+		}
+		plaintext, err := DecryptWithPrivateKey(privKey, ciphertext)
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+		}
+		if !bytes.Equal(plaintext, message) {
+			t.Errorf("decrypted %q instead of %q", plaintext, message)
+		}
+	}
+	first, _ := EncryptToPublicKey(Public(privKey, true), message)
+	second, _ := EncryptToPublicKey(Public(privKey, true), message)
+	if bytes.Equal(first, second) {
+		t.Errorf("encrypting twice should use different ephemeral keys")
+	}
+	empty, _ := EncryptToPublicKey(Public(privKey, true), nil)
+	if plaintext, err := DecryptWithPrivateKey(privKey, empty); err != nil || len(plaintext) != 0 {
+		t.Errorf("empty message should round trip, got %q %v", plaintext, err)
+	}
+}
+
+func TestDecryptWithPrivateKeyErrors(t *testing.T) {
+	privKey, _ := hex.DecodeString("0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d")
+	other, _ := hex.DecodeString("e9873d79c6d87dc0fb6a5778633389f4453213303da61f20bd67fc233aa33262")
+	ciphertext, _ := EncryptToPublicKey(Public(privKey, true), []byte("secret"))
+	if _, err := DecryptWithPrivateKey(other, ciphertext); err == nil {
+		t.Errorf("decrypting with another key should return an error")
+	}
+	for i := range ciphertext {
+		altered := append([]byte{}, ciphertext...)
+		altered[i] ^= 0x01
+		if _, err := DecryptWithPrivateKey(privKey, altered); err == nil {
+			t.Errorf("ciphertext altered at byte %d should return an error", i)
+		}
+	}
+	if _, err := DecryptWithPrivateKey(privKey, ciphertext[:92]); err == nil {
+		t.Errorf("short ciphertext should return an error")
+	}
+	// x of the generator with a wrong y, not on the curve
+	offCurve, _ := hex.DecodeString("0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b9")
+	if _, err := EncryptToPublicKey(offCurve, []byte("secret")); err == nil {
+		t.Errorf("public key not on the curve should return an error")
+	}
+}
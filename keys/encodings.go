@@ -0,0 +1,75 @@
+package keys
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// VerifyAllEncodings encodes a private key in every format of the package (compressed and uncompressed WIF on the network, hex,
+// 24 words mnemonic of the key, dice and coinflip sequences), decodes each one back and checks the key is unchanged.
+// The error names the first format that does not round-trip. The dice sequence has 100 rolls, enough for any key,
+// while the 99 rolls of FromDiceSequence cannot represent keys above 6^99-1
+func VerifyAllEncodings(privKey []byte, network Network) error {
+	if network.String() == "unknown" {
+		return fmt.Errorf("unknown network %d", network)
+	}
+	if len(privKey) != 32 || !isValidKey(new(big.Int).SetBytes(privKey)) {
+		return errors.New("given key is not acceptable as private key")
+	}
+	for _, compressed := range []bool{true, false} {
+		wif, err := ToWIFNetwork(privKey, compressed, network)
+		if err != nil {
+			return fmt.Errorf("wif encoding failed: %v", err)
+		}
+		key, wifCompressed, testnet, err := PrivateFromWIF(wif)
+		if err := checkRoundTrip("wif", privKey, key, err); err != nil {
+			return err
+		}
+		if wifCompressed != compressed || testnet != (network != MainNet) {
+			return fmt.Errorf("wif %v does not round-trip its compression or network", wif)
+		}
+	}
+	key, err := FromHexSequence(hex.EncodeToString(privKey))
+	if err := checkRoundTrip("hex", privKey, key, err); err != nil {
+		return err
+	}
+	mnemonic, err := KeyToMnemonic(privKey)
+	if err != nil {
+		return fmt.Errorf("mnemonic encoding failed: %v", err)
+	}
+	key, err = KeyFromMnemonic(mnemonic)
+	if err := checkRoundTrip("mnemonic", privKey, key, err); err != nil {
+		return err
+	}
+	baseSix := new(big.Int).SetBytes(privKey).Text(6)
+	baseSix = strings.Repeat("0", DiceSeqRequiredLength+1-len(baseSix)) + baseSix
+	dice := make([]byte, len(baseSix))
+	for i := 0; i < len(baseSix); i++ {
+		dice[i] = baseSix[i] + 1
+	}
+	key, err = FromDiceSequenceN(string(dice))
+	if err := checkRoundTrip("dice", privKey, key, err); err != nil {
+		return err
+	}
+	var flips strings.Builder
+	for _, b := range privKey {
+		fmt.Fprintf(&flips, "%08b", b)
+	}
+	key, err = FromCoinflipSequence(flips.String())
+	return checkRoundTrip("coinflip", privKey, key, err)
+}
+
+// checkRoundTrip returns an error naming the format if decoding failed or did not give back the key
+func checkRoundTrip(format string, privKey []byte, decoded []byte, err error) error {
+	if err != nil {
+		return fmt.Errorf("%v round-trip failed: %v", format, err)
+	}
+	if !bytes.Equal(decoded, privKey) {
+		return fmt.Errorf("%v round-trip failed: got %x", format, decoded)
+	}
+	return nil
+}
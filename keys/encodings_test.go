@@ -0,0 +1,38 @@
+package keys
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestVerifyAllEncodings(t *testing.T) {
+	vectors := []string{
+		"0000000000000000000000000000000000000000000000000000000000000001",
+		"00000000000000000000000000000000000000000000000000000000000000ff",
+		"0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d",
+		// above 6^99-1, beyond 99 dice
+		"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140",
+	}
+	for _, v := range vectors {
+		privKey, _ := hex.DecodeString(v)
+		for _, network := range Networks {
+			if err := VerifyAllEncodings(privKey, network); err != nil {
+				t.Errorf("key %v on %v failed due to %v", v, network, err)
+			}
+		}
+	}
+	wrong := []string{
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141",
+		"00000000000000000000000000000000000000000000000000000000000001",
+	}
+	for _, w := range wrong {
+		privKey, _ := hex.DecodeString(w)
+		if err := VerifyAllEncodings(privKey, MainNet); err == nil {
+			t.Errorf("key %v should return an error", w)
+		}
+	}
+	if err := VerifyAllEncodings(make([]byte, 32), Network(9)); err == nil {
+		t.Errorf("unknown network should return an error")
+	}
+}
@@ -0,0 +1,121 @@
+package keys
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// SourceDice identifies a sequence of dice rolls (1-6)
+const SourceDice = "dice"
+
+// SourceCoinflip identifies a sequence of coin flips (0-1)
+const SourceCoinflip = "coinflip"
+
+// SourceHex identifies a sequence of hex chars (0-9, a-f)
+const SourceHex = "hex"
+
+// minEntropyRatio is the minimum ratio between estimated and nominal bits for a sequence to pass the quality check
+const minEntropyRatio = 0.85
+
+// maxRunMargin is how much the longest run can exceed the run expected in a random sequence of the same length
+const maxRunMargin = 5
+
+// EntropyReportData describes the quality of the entropy of a sequence
+type EntropyReportData struct {
+	SourceType      string
+	Length          int
+	NominalBits     float64 // bits the sequence would carry if it were perfectly random
+	EstimatedBits   float64 // bits estimated from the observed symbol frequencies (Shannon entropy)
+	DistinctSymbols int
+	LongestRun      int
+	Pass            bool
+}
+
+// EntropyReport analyzes a dice, coinflip or hex sequence and reports the estimated entropy, the number of distinct symbols,
+// the longest run of a repeated symbol and whether the sequence looks random enough.
+// The check is advisory: a sequence can pass and still be predictable (e.g. 123456123456...), it only catches gross mistakes
+func EntropyReport(sequence string, sourceType string) (EntropyReportData, error) {
+	switch sourceType {
+	case SourceDice:
+		return analyzeSequence(sequence, sourceType, "123456")
+	case SourceCoinflip:
+		return analyzeSequence(sequence, sourceType, "01")
+	case SourceHex:
+		return analyzeSequence(strings.ToLower(sequence), sourceType, "0123456789abcdef")
+	default:
+		return EntropyReportData{}, fmt.Errorf("unknown source type %v, must be one of %v, %v, %v", sourceType, SourceDice, SourceCoinflip, SourceHex)
+	}
+}
+
+func analyzeSequence(sequence string, sourceType string, alphabet string) (EntropyReportData, error) {
+	report := EntropyReportData{SourceType: sourceType, Length: len(sequence)}
+	if len(sequence) == 0 {
+		return report, fmt.Errorf("given sequence is empty")
+	}
+	counts := make(map[byte]int)
+	run := 0
+	for i := 0; i < len(sequence); i++ {
+		c := sequence[i]
+		if strings.IndexByte(alphabet, c) < 0 {
+			return report, fmt.Errorf("char %c at position %d is not allowed in a %v sequence", c, i, sourceType)
+		}
+		counts[c]++
+		if i > 0 && sequence[i-1] == c {
+			run++
+		} else {
+			run = 1
+		}
+		if run > report.LongestRun {
+			report.LongestRun = run
+		}
+	}
+	base := float64(len(alphabet))
+	length := float64(len(sequence))
+	report.DistinctSymbols = len(counts)
+	report.NominalBits = length * math.Log2(base)
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / length
+		entropy -= p * math.Log2(p)
+	}
+	report.EstimatedBits = entropy * length
+	report.Pass = len(weaknesses(report, len(alphabet))) == 0
+	return report, nil
+}
+
+// SequenceEntropyWarning tells if a dice (base 6), coinflip (base 2) or hex (base 16) sequence looks non random: too few distinct symbols,
+// too long runs of the same symbol or too low estimated entropy, with a warning describing why. It is advisory, meant to be called
+// before FromDiceSequence or FromCoinflipSequence, and checks the same conditions as EntropyReport
+func SequenceEntropyWarning(sequence string, base int) (warning string, weak bool) {
+	sources := map[int]string{2: SourceCoinflip, 6: SourceDice, 16: SourceHex}
+	sourceType, ok := sources[base]
+	if !ok {
+		return fmt.Sprintf("cannot check base %d sequences, only 2, 6 and 16", base), false
+	}
+	report, err := EntropyReport(sequence, sourceType)
+	if err != nil {
+		return fmt.Sprintf("cannot check sequence: %v", err), false
+	}
+	reasons := weaknesses(report, base)
+	if len(reasons) == 0 {
+		return "", false
+	}
+	return fmt.Sprintf("your %v sequence looks non-random: %v", sourceType, strings.Join(reasons, ", ")), true
+}
+
+// weaknesses lists why a report of a sequence of symbols of base values fails the quality check
+func weaknesses(report EntropyReportData, base int) []string {
+	var reasons []string
+	if min := int(math.Min(float64(base), 3)); report.DistinctSymbols < min {
+		reasons = append(reasons, fmt.Sprintf("only %d distinct symbols", report.DistinctSymbols))
+	}
+	expectedRun := math.Ceil(math.Log(float64(report.Length)) / math.Log(float64(base)))
+	if float64(report.LongestRun) > expectedRun+maxRunMargin {
+		reasons = append(reasons, fmt.Sprintf("a run of %d identical symbols", report.LongestRun))
+	}
+	if report.EstimatedBits < minEntropyRatio*report.NominalBits {
+		reasons = append(reasons, fmt.Sprintf("estimated entropy %.1f of %.1f bits", report.EstimatedBits, report.NominalBits))
+	}
+	return reasons
+}
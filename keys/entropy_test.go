@@ -0,0 +1,78 @@
+package keys
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEntropyReport(t *testing.T) {
+	good := []struct {
+		sequence   string
+		sourceType string
+	}{
+		{"163452615243364125314625431652413265143256341265341526341562534162534162534162341562341526431526345", SourceDice},
+		{"1011001110001111010010110100101100011101001011011100101001011101001011101001010010111010010110100101100111010010101110100101001011101001010011101001011001011101001010110100101110100101101001010011101011010010100101110100101101010011010101001011010010101101", SourceCoinflip},
+		{"4440CD90151432BC082C6925A4A8D4CCFF2065017E9224D16563182C9AD8A7AA", SourceHex},
+	}
+	for _, v := range good {
+		report, err := EntropyReport(v.sequence, v.sourceType)
+		if err != nil {
+			t.Errorf("cannot analyze %v sequence due to %v", v.sourceType, err)
+			continue
+		}
+		if !report.Pass {
+			t.Errorf("%v sequence should pass the quality check: %+v", v.sourceType, report)
+		}
+	}
+	bad := []struct {
+		sequence   string
+		sourceType string
+	}{
+		{strings.Repeat("3", DiceSeqRequiredLength), SourceDice},
+		{strings.Repeat("12", DiceSeqRequiredLength/2) + "1", SourceDice},
+		{strings.Repeat("0", 20) + strings.Repeat("01", 118), SourceCoinflip},
+		{strings.Repeat("a", HexSeqRequiredLength), SourceHex},
+	}
+	for _, v := range bad {
+		report, err := EntropyReport(v.sequence, v.sourceType)
+		if err != nil {
+			t.Errorf("cannot analyze %v sequence due to %v", v.sourceType, err)
+			continue
+		}
+		if report.Pass {
+			t.Errorf("%v sequence %v should fail the quality check: %+v", v.sourceType, v.sequence, report)
+		}
+	}
+}
+
+func TestEntropyReportErrors(t *testing.T) {
+	if _, err := EntropyReport("123456", "cards"); err == nil {
+		t.Errorf("unknown source type should return an error")
+	}
+	if _, err := EntropyReport("", SourceDice); err == nil {
+		t.Errorf("empty sequence should return an error")
+	}
+	if _, err := EntropyReport("1234567", SourceDice); err == nil {
+		t.Errorf("dice sequence with a 7 should return an error")
+	}
+	if _, err := EntropyReport("0120", SourceCoinflip); err == nil {
+		t.Errorf("coinflip sequence with a 2 should return an error")
+	}
+}
+
+func TestSequenceEntropyWarning(t *testing.T) {
+	warning, weak := SequenceEntropyWarning(strings.Repeat("3", DiceSeqRequiredLength), 6)
+	if !weak || !strings.Contains(warning, "only 1 distinct symbols") || !strings.Contains(warning, "run of 99") {
+		t.Errorf("all 3s should be weak with a warning on variety and runs, got %v %q", weak, warning)
+	}
+	healthy := "163452615243364125314625431652413265143256341265341526341562534162534162534162341562341526431526345"
+	if warning, weak := SequenceEntropyWarning(healthy, 6); weak || warning != "" {
+		t.Errorf("healthy dice sequence should not be weak, got %v %q", weak, warning)
+	}
+	if warning, weak := SequenceEntropyWarning(healthy, 10); weak || warning == "" {
+		t.Errorf("base 10 should return a warning without being weak, got %v %q", weak, warning)
+	}
+	if warning, weak := SequenceEntropyWarning("1234567", 6); weak || warning == "" {
+		t.Errorf("invalid dice should return a warning without being weak, got %v %q", weak, warning)
+	}
+}
@@ -0,0 +1,156 @@
+package keys
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcutil/base58"
+)
+
+// ScriptTypeP2PKH identifies a legacy Pay-To-Public-Key-Hash script (BIP44)
+const ScriptTypeP2PKH = "P2PKH"
+
+// ScriptTypeP2SHP2WPKH identifies a SegWit Pay-To-Witness-Public-Key-Hash nested in a P2SH script (BIP49)
+const ScriptTypeP2SHP2WPKH = "P2SH-P2WPKH"
+
+// ScriptTypeP2WPKH identifies a native SegWit Pay-To-Witness-Public-Key-Hash script (BIP84)
+const ScriptTypeP2WPKH = "P2WPKH"
+
+// ScriptTypeP2SH identifies a Pay-To-Script-Hash script (BIP16)
+const ScriptTypeP2SH = "P2SH"
+
+// ScriptTypeP2WSH identifies a native SegWit Pay-To-Witness-Script-Hash script (BIP141)
+const ScriptTypeP2WSH = "P2WSH"
+
+// ScriptTypeP2TR identifies a Taproot Pay-To-Taproot script (BIP86)
+const ScriptTypeP2TR = "P2TR"
+
+// extendedKeyLength is the length of a serialized extended key (without checksum)
+const extendedKeyLength = 78
+
+// ExtendedKey is a BIP32 extended key, private or public depending on its version
+type ExtendedKey struct {
+	Version           uint32
+	Depth             byte
+	ParentFingerprint uint32
+	ChildNumber       uint32
+	ChainCode         []byte
+	Key               []byte // 32 bytes private key or 33 bytes compressed public key
+}
+
+type extendedVersion struct {
+	prefix     string
+	scriptType string
+	network    Network
+	private    bool
+}
+
+// extendedVersions maps the 4 version bytes of a serialized extended key to what they imply
+var extendedVersions = map[uint32]extendedVersion{
+	0x0488B21E: {"xpub", ScriptTypeP2PKH, MainNet, false},
+	0x0488ADE4: {"xprv", ScriptTypeP2PKH, MainNet, true},
+	0x049D7CB2: {"ypub", ScriptTypeP2SHP2WPKH, MainNet, false},
+	0x049D7878: {"yprv", ScriptTypeP2SHP2WPKH, MainNet, true},
+	0x04B24746: {"zpub", ScriptTypeP2WPKH, MainNet, false},
+	0x04B2430C: {"zprv", ScriptTypeP2WPKH, MainNet, true},
+	0x043587CF: {"tpub", ScriptTypeP2PKH, TestNet, false},
+	0x04358394: {"tprv", ScriptTypeP2PKH, TestNet, true},
+	0x044A5262: {"upub", ScriptTypeP2SHP2WPKH, TestNet, false},
+	0x044A4E28: {"uprv", ScriptTypeP2SHP2WPKH, TestNet, true},
+	0x045F1CF6: {"vpub", ScriptTypeP2WPKH, TestNet, false},
+	0x045F18BC: {"vprv", ScriptTypeP2WPKH, TestNet, true},
+}
+
+// ScriptTypeFromExtendedPrefix returns the script type and the network implied by the prefix (xpub, ypub, zpub, tpub, upub, vpub and the private counterparts) of a base58 encoded extended key
+func ScriptTypeFromExtendedPrefix(s string) (scriptType string, network Network, err error) {
+	decoded := base58.Decode(s)
+	if len(decoded) != extendedKeyLength+4 {
+		return "", MainNet, fmt.Errorf("invalid extended key: decoded length %d, must be %d", len(decoded), extendedKeyLength+4)
+	}
+	payload := decoded[:extendedKeyLength]
+	hashOne := sha256.Sum256(payload)
+	hashTwo := sha256.Sum256(hashOne[:])
+	if subtle.ConstantTimeCompare(hashTwo[:4], decoded[extendedKeyLength:]) != 1 {
+		return "", MainNet, errors.New("cannot decode extended key because checksum is wrong")
+	}
+	version, ok := extendedVersions[binary.BigEndian.Uint32(payload[:4])]
+	if !ok {
+		return "", MainNet, fmt.Errorf("unknown extended key version %x", payload[:4])
+	}
+	return version.scriptType, version.network, nil
+}
+
+// IsPrivate tells if the extended key holds a private key, according to its version
+func (k *ExtendedKey) IsPrivate() bool {
+	return extendedVersions[k.Version].private
+}
+
+// Serialize returns the raw 78 bytes BIP32 serialization of the extended key:
+// version(4) + depth(1) + parent fingerprint(4) + child number(4) + chain code(32) + key(33)
+func (k *ExtendedKey) Serialize() ([]byte, error) {
+	version, ok := extendedVersions[k.Version]
+	if !ok {
+		return nil, fmt.Errorf("unknown extended key version %x", k.Version)
+	}
+	if len(k.ChainCode) != 32 {
+		return nil, fmt.Errorf("chain code is %d bytes long, must be 32", len(k.ChainCode))
+	}
+	serialized := make([]byte, extendedKeyLength)
+	binary.BigEndian.PutUint32(serialized[0:4], k.Version)
+	serialized[4] = k.Depth
+	binary.BigEndian.PutUint32(serialized[5:9], k.ParentFingerprint)
+	binary.BigEndian.PutUint32(serialized[9:13], k.ChildNumber)
+	copy(serialized[13:45], k.ChainCode)
+	if version.private {
+		if len(k.Key) != 32 {
+			return nil, fmt.Errorf("private key is %d bytes long, must be 32", len(k.Key))
+		}
+		copy(serialized[46:], k.Key)
+	} else {
+		if len(k.Key) != 33 || (k.Key[0] != 0x02 && k.Key[0] != 0x03) {
+			return nil, errors.New("public key must be 33 bytes in compressed format")
+		}
+		copy(serialized[45:], k.Key)
+	}
+	return serialized, nil
+}
+
+// DeserializeExtendedKey parses the raw 78 bytes BIP32 serialization of an extended key
+func DeserializeExtendedKey(data []byte) (*ExtendedKey, error) {
+	if len(data) != extendedKeyLength {
+		return nil, fmt.Errorf("extended key is %d bytes long, must be %d", len(data), extendedKeyLength)
+	}
+	key := &ExtendedKey{
+		Version:           binary.BigEndian.Uint32(data[0:4]),
+		Depth:             data[4],
+		ParentFingerprint: binary.BigEndian.Uint32(data[5:9]),
+		ChildNumber:       binary.BigEndian.Uint32(data[9:13]),
+		ChainCode:         append([]byte{}, data[13:45]...),
+	}
+	version, ok := extendedVersions[key.Version]
+	if !ok {
+		return nil, fmt.Errorf("unknown extended key version %x", data[0:4])
+	}
+	if key.Depth == 0 && (key.ParentFingerprint != 0 || key.ChildNumber != 0) {
+		return nil, errors.New("master extended key cannot have parent fingerprint or child number")
+	}
+	if version.private {
+		if data[45] != 0x00 {
+			return nil, fmt.Errorf("private extended key must have 0x00 before the key, found %x", data[45])
+		}
+		if !isValidKey(new(big.Int).SetBytes(data[46:])) {
+			return nil, errors.New("extended key holds a number not acceptable as private key")
+		}
+		key.Key = append([]byte{}, data[46:]...)
+	} else {
+		if data[45] != 0x02 && data[45] != 0x03 {
+			return nil, fmt.Errorf("public extended key must hold a compressed public key, found prefix %x", data[45])
+		}
+		key.Key = append([]byte{}, data[45:]...)
+	}
+	return key, nil
+}
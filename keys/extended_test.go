@@ -0,0 +1,105 @@
+package keys
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+)
+
+func TestScriptTypeFromExtendedPrefix(t *testing.T) {
+	// https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki#test-vector-1
+	// https://github.com/bitcoin/bips/blob/master/bip-0049.mediawiki#test-vectors
+	// https://github.com/bitcoin/bips/blob/master/bip-0084.mediawiki#test-vectors
+	extendedKeys := []struct {
+		key        string
+		scriptType string
+		network    Network
+	}{
+		{"xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8", ScriptTypeP2PKH, MainNet},
+		{"xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi", ScriptTypeP2PKH, MainNet},
+		{"zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs", ScriptTypeP2WPKH, MainNet},
+		{"tpubD6NzVbkrYhZ4XgiXtGrdW5XDAPFCL9h7we1vwNCpn8tGbBcgfVYjXyhWo4E1xkh56hjod1RhGjxbaTLV3X4FyWuejifB9jusQ46QzG87VKp", ScriptTypeP2PKH, TestNet},
+	}
+	for _, v := range extendedKeys {
+		scriptType, network, err := ScriptTypeFromExtendedPrefix(v.key)
+		if err != nil {
+			t.Errorf("cannot detect script type of %v due to %v", v.key, err)
+			continue
+		}
+		if scriptType != v.scriptType || network != v.network {
+			t.Errorf("%v should be %v on %v but is %v on %v", v.key, v.scriptType, v.network, scriptType, network)
+		}
+	}
+}
+
+func TestScriptTypeFromExtendedPrefixErrors(t *testing.T) {
+	invalid := []string{
+		"",
+		"xpub",
+		"xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet9",
+		"5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ",
+	}
+	for _, s := range invalid {
+		if _, _, err := ScriptTypeFromExtendedPrefix(s); err == nil {
+			t.Errorf("invalid extended key %v was accepted", s)
+		}
+	}
+	// the checksum error must not echo a private key into logs
+	xprv := "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHj"
+	_, _, err := ScriptTypeFromExtendedPrefix(xprv)
+	if err == nil || strings.Contains(err.Error(), xprv[:20]) {
+		t.Errorf("checksum error should not contain the key, got %v", err)
+	}
+}
+
+func TestSerializeExtendedKey(t *testing.T) {
+	// https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki#test-vector-1
+	extendedKeys := []string{
+		"xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8",
+		"xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi",
+		"xprv9uHRZZhk6KAJC1avXpDAp4MDc3sQKNxDiPvvkX8Br5ngLNv1TxvUxt4cV1rGL5hj6KCesnDYUhd7oWgT11eZG7XnxHrnYeSvkzY7d2bhkJ7",
+	}
+	for _, s := range extendedKeys {
+		raw := base58.Decode(s)[:extendedKeyLength]
+		key, err := DeserializeExtendedKey(raw)
+		if err != nil {
+			t.Errorf("cannot deserialize %v due to %v", s, err)
+			continue
+		}
+		if key.IsPrivate() != strings.HasPrefix(s, "xprv") {
+			t.Errorf("%v private should be %v", s, !key.IsPrivate())
+		}
+		serialized, err := key.Serialize()
+		if err != nil {
+			t.Errorf("cannot serialize %v due to %v", s, err)
+		}
+		if !bytes.Equal(raw, serialized) {
+			t.Errorf("serialization of %v should be %x but is %x", s, raw, serialized)
+		}
+	}
+}
+
+func TestDeserializeExtendedKeyErrors(t *testing.T) {
+	raw := base58.Decode("xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi")[:extendedKeyLength]
+	if _, err := DeserializeExtendedKey(raw[:77]); err == nil {
+		t.Errorf("77 bytes extended key should be rejected")
+	}
+	corrupt := func(index int, value byte) []byte {
+		c := append([]byte{}, raw...)
+		c[index] = value
+		return c
+	}
+	invalid := map[string][]byte{
+		"unknown version":           corrupt(0, 0x05),
+		"master with parent":        corrupt(5, 0x01),
+		"private without 0x00":      corrupt(45, 0x02),
+		"public with private value": corrupt(3, 0x1E),
+	}
+	for description, data := range invalid {
+		if _, err := DeserializeExtendedKey(data); err == nil {
+			t.Errorf("extended key with %v should be rejected", description)
+		}
+	}
+}
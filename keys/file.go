@@ -0,0 +1,31 @@
+package keys
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec"
+)
+
+// FromFile returns a private key generated from the sha256 of the whole content read from r (e.g. a photo), reduced modulo the curve order.
+// The same file always gives the same key, so the key is only as secret and as unpredictable as the file:
+// never use a file that is public, shared, or can be guessed (a well known picture, a downloaded document)
+func FromFile(r io.Reader) (key []byte, err error) {
+	hash := sha256.New()
+	n, err := io.Copy(hash, r)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read file due to %v", err)
+	}
+	if n == 0 {
+		return nil, errors.New("given file is empty")
+	}
+	bi := new(big.Int).SetBytes(hash.Sum(nil))
+	bi.Mod(bi, btcec.S256().N)
+	if !isValidKey(bi) {
+		return nil, errors.New("file digest represents a number not acceptable as private key")
+	}
+	return bi.FillBytes(make([]byte, 32)), nil
+}
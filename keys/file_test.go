@@ -0,0 +1,42 @@
+package keys
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type failingReader struct{}
+
+func (failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("disk error")
+}
+
+func TestFromFile(t *testing.T) {
+	key, err := FromFile(strings.NewReader("abc"))
+	if err != nil {
+		t.Errorf("failed due to %v\n", err)
+	}
+	if hex.EncodeToString(key) != "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad" {
+		t.Errorf("unexpected key %x", key)
+	}
+	block := make([]byte, 256)
+	for i := range block {
+		block[i] = byte(i)
+	}
+	key, err = FromFile(bytes.NewReader(bytes.Repeat(block, 4000)))
+	if err != nil {
+		t.Errorf("failed due to %v\n", err)
+	}
+	if hex.EncodeToString(key) != "062af9ccd890ba3d067ca7150278bcc420069bd82f6e41161029303dfd6d661e" {
+		t.Errorf("unexpected key %x", key)
+	}
+	if _, err = FromFile(strings.NewReader("")); err == nil {
+		t.Errorf("empty file should return an error")
+	}
+	if _, err = FromFile(failingReader{}); err == nil {
+		t.Errorf("read error should be returned")
+	}
+}
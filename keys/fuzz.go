@@ -0,0 +1,36 @@
+package keys
+
+// WIFFuzzSeeds returns valid and malformed WIF strings to seed the corpus of fuzz tests of WIF parsers
+func WIFFuzzSeeds() []string {
+	return []string{
+		"5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ",
+		"KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617",
+		"cMzLdeGd5vEqxB8B6VFQoRopQ3sLAAvEzDAoQgvX54xwofSWj1fx",
+		"5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTj",
+		"",
+		"1",
+		"123",
+		"11111",
+		"0OIl",
+		"KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617KwdMAjGmerYanjeui5SHS7Jkmp",
+	}
+}
+
+// SequenceFuzzSeeds returns valid and malformed dice, coinflip and hex sequences to seed the corpus of fuzz tests of sequence parsers
+func SequenceFuzzSeeds() []string {
+	return []string{
+		"111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111112",
+		"666666666666666666666666666666666666666666666666666666666666666666666666666666666666666666666666666",
+		"0000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000001",
+		"0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d",
+		"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141",
+		"",
+		"0",
+		"-1",
+		"+1",
+		"0x0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa",
+		"1_000",
+		"7",
+		"\xff",
+	}
+}
@@ -0,0 +1,82 @@
+package keys
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcutil/base58"
+)
+
+// weakKeyBound is the bound below which a key (or its distance from the curve order) is small enough to be found by a brute force search
+var weakKeyBound = new(big.Int).Lsh(big.NewInt(1), 64)
+
+// weakPassphrases are brain wallet passphrases whose sha256, used as private key, is known to be swept within seconds
+var weakPassphrases = []string{"", "password", "bitcoin", "satoshi", "correct horse battery staple", "hello", "test", "123456"}
+
+// HealthReport summarizes the hygiene of a collection of private keys, each entry listing the indexes of the keys concerned
+type HealthReport struct {
+	Total        int
+	Compressed   int
+	Uncompressed int
+	Duplicates   map[string][]int // P2PKH address (of the compressed public key) shared by more than one key
+	Weak         []int            // keys that are tiny, close to the curve order, a repeated byte or the sha256 of a known passphrase
+	OutOfRange   []int            // keys that are zero or not below the curve order
+}
+
+// WalletHealth checks a collection of private keys, each either 32 bytes (uncompressed) or 33 bytes ending with 0x01 (compressed, as in WIF),
+// and reports how many are compressed, which ones appear more than once (also with a different compression), which ones are known to be weak
+// and which ones are out of range. Weak and out of range keys must never hold funds; duplicates are harmless but usually mean a mistake in the import
+func WalletHealth(keys [][]byte, network Network) (HealthReport, error) {
+	if network.String() == "unknown" {
+		return HealthReport{}, fmt.Errorf("unknown network %d", network)
+	}
+	report := HealthReport{Total: len(keys), Duplicates: make(map[string][]int)}
+	indexes := make(map[string][]int)
+	for i, key := range keys {
+		switch {
+		case len(key) == 32:
+			report.Uncompressed++
+		case len(key) == 33 && key[32] == 0x01:
+			report.Compressed++
+			key = key[:32]
+		default:
+			return HealthReport{}, fmt.Errorf("key at index %d is %d bytes long, must be 32 or 33 ending with 0x01", i, len(key))
+		}
+		if !isValidKey(new(big.Int).SetBytes(key)) {
+			report.OutOfRange = append(report.OutOfRange, i)
+			continue
+		}
+		if isWeakKey(key) {
+			report.Weak = append(report.Weak, i)
+		}
+		address := base58.CheckEncode(Hashed(Public(key, true)), network.PubKeyHashVersion())
+		indexes[address] = append(indexes[address], i)
+	}
+	for address, keyIndexes := range indexes {
+		if len(keyIndexes) > 1 {
+			report.Duplicates[address] = keyIndexes
+		}
+	}
+	return report, nil
+}
+
+// isWeakKey tells if a valid 32 bytes private key is one that attackers are known to scan
+func isWeakKey(key []byte) bool {
+	value := new(big.Int).SetBytes(key)
+	if value.Cmp(weakKeyBound) < 0 || new(big.Int).Sub(btcec.S256().N, value).Cmp(weakKeyBound) <= 0 {
+		return true
+	}
+	if bytes.Count(key, key[:1]) == len(key) {
+		return true
+	}
+	for _, passphrase := range weakPassphrases {
+		hash := sha256.Sum256([]byte(passphrase))
+		if bytes.Equal(key, hash[:]) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package keys
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"testing"
+)
+
+func TestWalletHealth(t *testing.T) {
+	good, _ := hex.DecodeString("0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d")
+	other, _ := hex.DecodeString("e9873d79c6d87dc0fb6a5778633389f4453213303da61f20bd67fc233aa33262")
+	small := make([]byte, 32)
+	small[31] = 7
+	brain := sha256.Sum256([]byte("password"))
+	order, _ := hex.DecodeString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141")
+	keySet := [][]byte{
+		good,
+		append(append([]byte{}, other...), 0x01),
+		append(append([]byte{}, good...), 0x01),
+		small,
+		brain[:],
+		bytes.Repeat([]byte{0x42}, 32),
+		make([]byte, 32),
+		order,
+	}
+	report, err := WalletHealth(keySet, MainNet)
+	if err != nil {
+		t.Errorf("failed due to %v\n", err)
+	}
+	if report.Total != 8 || report.Compressed != 2 || report.Uncompressed != 6 {
+		t.Errorf("unexpected counts %d total, %d compressed, %d uncompressed", report.Total, report.Compressed, report.Uncompressed)
+	}
+	expectedDuplicates := map[string][]int{"1LoVGDgRs9hTfTNJNuXKSpywcbdvwRXpmK": {0, 2}}
+	if !reflect.DeepEqual(report.Duplicates, expectedDuplicates) {
+		t.Errorf("unexpected duplicates %v", report.Duplicates)
+	}
+	if !reflect.DeepEqual(report.Weak, []int{3, 4, 5}) {
+		t.Errorf("unexpected weak keys %v", report.Weak)
+	}
+	if !reflect.DeepEqual(report.OutOfRange, []int{6, 7}) {
+		t.Errorf("unexpected out of range keys %v", report.OutOfRange)
+	}
+	report, err = WalletHealth([][]byte{good, other}, TestNet)
+	if err != nil {
+		t.Errorf("failed due to %v\n", err)
+	}
+	if len(report.Duplicates) != 0 || len(report.Weak) != 0 || len(report.OutOfRange) != 0 {
+		t.Errorf("healthy keys reported as %+v", report)
+	}
+}
+
+func TestWalletHealthErrors(t *testing.T) {
+	good, _ := hex.DecodeString("0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d")
+	if _, err := WalletHealth([][]byte{good[:31]}, MainNet); err == nil {
+		t.Errorf("short key should return an error")
+	}
+	if _, err := WalletHealth([][]byte{append(append([]byte{}, good...), 0x02)}, MainNet); err == nil {
+		t.Errorf("33 bytes key without compression flag should return an error")
+	}
+	if _, err := WalletHealth([][]byte{good}, Network(5)); err == nil {
+		t.Errorf("unknown network should return an error")
+	}
+}
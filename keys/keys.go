@@ -3,14 +3,19 @@ package keys
 import (
 	"crypto/ecdsa"
 	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"math/big"
-	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/btcsuite/btcd/btcec"
 	"github.com/btcsuite/btcutil/base58"
+	"github.com/savardiego/cashline/segwit"
 	bip39 "github.com/tyler-smith/go-bip39"
+	"github.com/tyler-smith/go-bip39/wordlists"
 	"golang.org/x/crypto/ripemd160"
 )
 
@@ -23,6 +28,9 @@ const CoinflipSeqRequiredLength = 256
 // HexSeqRequiredLength is the number of required hex chars
 const HexSeqRequiredLength = 64
 
+// MaxSequenceLength is the maximum number of chars accepted by FromBaseNSequence and FromDiceSequenceN
+const MaxSequenceLength = 1024
+
 var maxValueForKey *big.Int
 var minValueForKey *big.Int
 
@@ -33,28 +41,64 @@ func init() {
 	minValueForKey.SetString("1", 16)
 }
 
-// PrivateFromWIF decodes a base58 encoded key (compressed or uncompressed) (WIF Wallet Import Format) to []byte
-func PrivateFromWIF(keyString string) (key []byte, compressed bool, err error) {
-	// Decoding key using base58
-	decoded := base58.Decode(keyString)
-	if decoded[0] != 0x80 {
-		return nil, false, fmt.Errorf("input value is not a valid mainnet key")
+// PrivateFromWIF decodes a base58 encoded key (compressed or uncompressed) (WIF Wallet Import Format) to []byte,
+// telling if it is a mainnet (version 0x80) or a testnet (version 0xEF, shared by regtest and signet) key
+func PrivateFromWIF(keyString string) (key []byte, compressed bool, testnet bool, err error) {
+	// Decoding key using base58, in constant time since it is a secret
+	decoded, err := decodeBase58ConstantTime(keyString)
+	if err != nil {
+		return nil, false, false, fmt.Errorf("invalid WIF: %v", err)
+	}
+	// version byte, 32 bytes key, optional compression flag, 4 bytes checksum
+	if len(decoded) < 37 {
+		return nil, false, false, fmt.Errorf("invalid WIF: decoded length %d too short", len(decoded))
+	}
+	if len(decoded) > 38 {
+		return nil, false, false, fmt.Errorf("invalid WIF: decoded length %d too long", len(decoded))
+	}
+	switch decoded[0] {
+	case MainNet.WIFVersion():
+	case TestNet.WIFVersion():
+		testnet = true
+	default:
+		return nil, false, false, fmt.Errorf("input value is not a valid mainnet or testnet key, version 0x%02x", decoded[0])
 	}
 	checkSum := decoded[len(decoded)-4:]
 	hashOne := sha256.Sum256(decoded[:len(decoded)-4])
 	hashTwo := sha256.Sum256(hashOne[:])
 	newCheckSum := hashTwo[:4]
-	if string(newCheckSum) != string(checkSum) {
-		return nil, false, fmt.Errorf("cannot decode private key %v because checksum is wrong", key)
+	if subtle.ConstantTimeCompare(newCheckSum, checkSum) != 1 {
+		return nil, false, false, fmt.Errorf("cannot decode private key %v because checksum is wrong", keyString)
 	}
-	decKey := decoded[1 : len(decoded)-4]
-	key = decKey
-	compressed = false
-	if len(decKey) == 33 && decKey[len(decKey)-1] == 0x01 {
+	key = decoded[1:33]
+	if len(decoded) == 38 {
+		if decoded[33] != 0x01 {
+			return nil, false, false, fmt.Errorf("invalid WIF: compression flag is 0x%02x, must be 0x01", decoded[33])
+		}
 		compressed = true
-		key = decKey[:32]
 	}
-	return key, compressed, nil
+	return key, compressed, testnet, nil
+}
+
+// VerifyWIF tells if a WIF decodes to a usable private key, with its compression and network, without returning the key.
+// Invalid base58, a wrong version byte, a wrong checksum and a key out of range give different errors
+func VerifyWIF(wif string) (valid bool, compressed bool, testnet bool, err error) {
+	key, compressed, testnet, err := PrivateFromWIF(wif)
+	if err != nil {
+		return false, false, false, err
+	}
+	defer func() {
+		for i := range key {
+			key[i] = 0
+		}
+	}()
+	if len(key) != 32 {
+		return false, false, false, fmt.Errorf("invalid WIF: key is %d bytes long, must be 32 (with a 0x01 suffix when compressed)", len(key))
+	}
+	if !isValidKey(new(big.Int).SetBytes(key)) {
+		return false, false, false, errors.New("invalid WIF: key is zero or not below the curve order")
+	}
+	return true, compressed, testnet, nil
 }
 
 // FromDiceSequence returns a private key generated from a base6 sequence of 99 0-5 chars
@@ -69,6 +113,132 @@ func FromDiceSequence(sequence string) (key []byte, err error) {
 	return privKey, nil
 }
 
+// FromDiceSequenceZeroBased returns a private key generated from a sequence of 99 dice rolls recorded with faces 0-5,
+// as some dice and hardware random generators label them: each char is already a base6 digit. FromDiceSequence expects faces 1-6,
+// DetectDiceIndexing tells which labeling a sequence uses
+func FromDiceSequenceZeroBased(sequence string) (key []byte, err error) {
+	if len(sequence) != DiceSeqRequiredLength {
+		return nil, fmt.Errorf("given sequence is %d long, must be %d", len(sequence), DiceSeqRequiredLength)
+	}
+	privKey, err := diceKeyFaces(sequence, '0')
+	if err != nil {
+		return nil, fmt.Errorf("cannot read sequence: %v", err)
+	}
+	return privKey, nil
+}
+
+// FromDiceSequenceN returns a private key generated from a sequence of dice rolls (1-6) of up to MaxSequenceLength rolls, read as a base6 number
+// like FromDiceSequence. Each roll carries log2(6) ~ 2.585 bits: roll 99 times, the most that always give a valid key (6^99-1 is below
+// the curve order) with ~255.9 bits. Shorter sequences give valid but weaker keys; from 100 rolls on most sequences are above
+// the curve order (about 82% of 100 rolls) and must be rerolled
+func FromDiceSequenceN(sequence string) (key []byte, err error) {
+	if len(sequence) == 0 {
+		return nil, errors.New("given sequence is empty")
+	}
+	if len(sequence) > MaxSequenceLength {
+		return nil, fmt.Errorf("given sequence is %d long, must be at most %d", len(sequence), MaxSequenceLength)
+	}
+	privKey, err := diceKey(sequence)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read sequence: %v", err)
+	}
+	return privKey, nil
+}
+
+// HexToDiceEquivalent returns the 99 dice sequence (1-6 chars) that FromDiceSequence turns into the same key as a 64 chars hex key:
+// the key written in base 6, padded with zeros to 99 digits, each digit plus one. Keys above 6^99-1 (about 6% of all keys)
+// cannot be represented by 99 dice and return an error
+func HexToDiceEquivalent(hexSequence string) (string, error) {
+	if len(hexSequence) != HexSeqRequiredLength {
+		return "", fmt.Errorf("given sequence is %d long, must be %d", len(hexSequence), HexSeqRequiredLength)
+	}
+	key, err := hexKey(hexSequence)
+	if err != nil {
+		return "", fmt.Errorf("cannot read sequence: %v", err)
+	}
+	baseSix := new(big.Int).SetBytes(key).Text(6)
+	if len(baseSix) > DiceSeqRequiredLength {
+		return "", fmt.Errorf("key is above 6^%d-1, the biggest number %d dice can represent", DiceSeqRequiredLength, DiceSeqRequiredLength)
+	}
+	baseSix = strings.Repeat("0", DiceSeqRequiredLength-len(baseSix)) + baseSix
+	dice := make([]byte, len(baseSix))
+	for i := 0; i < len(baseSix); i++ {
+		dice[i] = baseSix[i] + 1
+	}
+	fromDice, err := FromDiceSequence(string(dice))
+	if err != nil || new(big.Int).SetBytes(fromDice).Cmp(new(big.Int).SetBytes(key)) != 0 {
+		return "", fmt.Errorf("dice sequence %s does not give back the hex key", dice)
+	}
+	return string(dice), nil
+}
+
+// FromDiceSessions returns a private key generated from dice rolled in several sessions (1-6 chars each), at least 99 rolls in total.
+// The sessions are joined in order into a single sequence and the key is the sha256 of its ASCII chars, reduced modulo the curve order:
+// only the order of the rolls matters, so {"1234", "56"} and {"12", "3456"} give the same key, and the sessions must be kept in order
+func FromDiceSessions(sessions []string) ([]byte, error) {
+	var joined strings.Builder
+	for s, session := range sessions {
+		if len(session) == 0 {
+			return nil, fmt.Errorf("session %d is empty", s+1)
+		}
+		for i := 0; i < len(session); i++ {
+			if session[i] < '1' || session[i] > '6' {
+				return nil, fmt.Errorf("session %d: char %c at position %d is not a die face (1-6)", s+1, session[i], i)
+			}
+		}
+		joined.WriteString(session)
+	}
+	if joined.Len() < DiceSeqRequiredLength {
+		return nil, fmt.Errorf("sessions have %d rolls in total, must be at least %d", joined.Len(), DiceSeqRequiredLength)
+	}
+	if joined.Len() > MaxSequenceLength {
+		return nil, fmt.Errorf("sessions have %d rolls in total, must be at most %d", joined.Len(), MaxSequenceLength)
+	}
+	hash := sha256.Sum256([]byte(joined.String()))
+	bi := new(big.Int).SetBytes(hash[:])
+	bi.Mod(bi, btcec.S256().N)
+	if !isValidKey(bi) {
+		return nil, errors.New("sessions digest represents a number not acceptable as private key")
+	}
+	return bi.FillBytes(make([]byte, 32)), nil
+}
+
+// Dice indexing reported by DetectDiceIndexing
+const (
+	DiceIndexingOneBased  = "1-6"
+	DiceIndexingZeroBased = "0-5"
+	DiceIndexingAmbiguous = "ambiguous"
+)
+
+// DetectDiceIndexing reports whether a dice sequence was recorded with faces 1-6 (it has a 6 and no 0), as FromDiceSequence expects,
+// or with faces 0-5 (it has a 0 and no 6), which would silently give a different key. Sequences with neither are ambiguous
+func DetectDiceIndexing(sequence string) (indexing string, err error) {
+	if len(sequence) == 0 {
+		return "", errors.New("given sequence is empty")
+	}
+	hasZero, hasSix := false, false
+	for i := 0; i < len(sequence); i++ {
+		switch c := sequence[i]; {
+		case c == '0':
+			hasZero = true
+		case c == '6':
+			hasSix = true
+		case c < '1' || c > '5':
+			return "", fmt.Errorf("char %c at position %d is not a dice face", c, i)
+		}
+	}
+	switch {
+	case hasZero && hasSix:
+		return "", errors.New("sequence contains both 0 and 6, it cannot be a dice sequence with either indexing")
+	case hasSix:
+		return DiceIndexingOneBased, nil
+	case hasZero:
+		return DiceIndexingZeroBased, nil
+	default:
+		return DiceIndexingAmbiguous, nil
+	}
+}
+
 // FromCoinflipSequence returns a private key generated from a base2 sequence of 256 0-1 chars
 func FromCoinflipSequence(sequence string) (key []byte, err error) {
 	if len(sequence) != CoinflipSeqRequiredLength {
@@ -81,9 +251,121 @@ func FromCoinflipSequence(sequence string) (key []byte, err error) {
 	return privKey, nil
 }
 
+// FromHexSequence returns a private key generated from a base16 sequence of 64 0-9, a-f chars
+func FromHexSequence(sequence string) (key []byte, err error) {
+	if len(sequence) != HexSeqRequiredLength {
+		return nil, fmt.Errorf("given sequence is %d long, must be %d", len(sequence), HexSeqRequiredLength)
+	}
+	privKey, err := hexKey(sequence)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read sequence: %v", err)
+	}
+	return privKey, nil
+}
+
+// FromBaseNSequence returns a private key generated from a sequence of digits (0-9, a-z) in the given base (2-36).
+// The sequence is accumulated one char at a time and rejected as soon as the value exceeds the curve order,
+// so the memory used does not depend on the length of the input, which is anyway capped at MaxSequenceLength chars
+func FromBaseNSequence(sequence string, base int) (key []byte, err error) {
+	if base < 2 || base > 36 {
+		return nil, fmt.Errorf("base %d not supported, must be between 2 and 36", base)
+	}
+	if len(sequence) == 0 {
+		return nil, errors.New("given sequence is empty")
+	}
+	if len(sequence) > MaxSequenceLength {
+		return nil, fmt.Errorf("given sequence is %d long, must be at most %d", len(sequence), MaxSequenceLength)
+	}
+	bi := new(big.Int)
+	bigBase := big.NewInt(int64(base))
+	digit := new(big.Int)
+	for i := 0; i < len(sequence); i++ {
+		d, ok := digitValue(sequence[i])
+		if !ok || d >= base {
+			return nil, fmt.Errorf("char %c at position %d is not a valid base %d digit", sequence[i], i, base)
+		}
+		bi.Mul(bi, bigBase)
+		bi.Add(bi, digit.SetInt64(int64(d)))
+		if bi.Cmp(maxValueForKey) > 0 {
+			return nil, errors.New("input sequence represents a number not acceptable as private key")
+		}
+	}
+	if !isValidKey(bi) {
+		return nil, errors.New("input sequence represents a number not acceptable as private key")
+	}
+	return padKey(bi.Bytes()), nil
+}
+
+// PadHexKey returns the 32 bytes private key represented by a hex string of 1 to 64 chars.
+// Keys shorter than 64 chars, typically because some tool stripped the leading zeros, are left-padded with zeros
+func PadHexKey(hexKey string) (key []byte, err error) {
+	if len(hexKey) == 0 {
+		return nil, errors.New("given hex key is empty")
+	}
+	if len(hexKey) > HexSeqRequiredLength {
+		return nil, fmt.Errorf("given hex key is %d long, must be at most %d", len(hexKey), HexSeqRequiredLength)
+	}
+	for i := 0; i < len(hexKey); i++ {
+		if d, ok := digitValue(hexKey[i]); !ok || d >= 16 {
+			return nil, fmt.Errorf("char %c at position %d is not a valid hex char", hexKey[i], i)
+		}
+	}
+	bi, _ := new(big.Int).SetString(hexKey, 16)
+	if !isValidKey(bi) {
+		return nil, errors.New("input hex key represents a number not acceptable as private key")
+	}
+	return bi.FillBytes(make([]byte, 32)), nil
+}
+
+// FromSeedBytes returns the 32 bytes private key of a 16 to 32 bytes entropy blob (e.g. scanned from a QR code).
+// A 32 bytes blob is the key itself and must be in the valid range. Shorter blobs are stretched to sha256(seed) mod n:
+// reading them as a number would give a key below 2^(8*len), which can be recovered in about 2^(4*len) steps once its public key is known
+func FromSeedBytes(seed []byte) (key []byte, err error) {
+	if len(seed) < 16 || len(seed) > 32 {
+		return nil, fmt.Errorf("given seed is %d bytes long, must be between 16 and 32", len(seed))
+	}
+	bi := new(big.Int).SetBytes(seed)
+	if len(seed) < 32 {
+		hash := sha256.Sum256(seed)
+		bi.SetBytes(hash[:])
+		bi.Mod(bi, btcec.S256().N)
+	}
+	if !isValidKey(bi) {
+		return nil, errors.New("input seed represents a number not acceptable as private key")
+	}
+	return bi.FillBytes(make([]byte, 32)), nil
+}
+
+// digitValue returns the value of a 0-9, a-z (or A-Z) digit
+func digitValue(c byte) (int, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0'), true
+	case c >= 'a' && c <= 'z':
+		return int(c-'a') + 10, true
+	case c >= 'A' && c <= 'Z':
+		return int(c-'A') + 10, true
+	}
+	return 0, false
+}
+
 // ToWIF encode a private key (given as a hex string) to WIF (Wallet IMport Format) compressed or uncompressed
 func ToWIF(privKey []byte, compressed bool) (string, error) {
-	first := append([]byte{0x80}, privKey...)
+	return ToWIFNetwork(privKey, compressed, MainNet)
+}
+
+// ToWIFTestnet encode a private key to testnet WIF (version byte 0xEF) compressed or uncompressed
+func ToWIFTestnet(privKey []byte, compressed bool) (string, error) {
+	return ToWIFNetwork(privKey, compressed, TestNet)
+}
+
+// ToWIFNetwork encode a 32 bytes private key to WIF (Wallet IMport Format) compressed or uncompressed for the given network.
+// Shorter keys, like the output of big.Int.Bytes() that dropped leading zeros, are rejected rather than encoded as a different key
+func ToWIFNetwork(privKey []byte, compressed bool, network Network) (string, error) {
+	if len(privKey) != 32 {
+		return "", fmt.Errorf("private key is %d bytes long, must be 32", len(privKey))
+	}
+	first := append([]byte{network.WIFVersion()}, privKey...)
 	if compressed {
 		first = append(first, 0x01)
 	}
@@ -95,7 +377,36 @@ func ToWIF(privKey []byte, compressed bool) (string, error) {
 	return encoded, nil
 }
 
-// Public derivates a public key in compressed or uncompressed format from a private key
+// WIFLengthBounds returns the minimum and maximum length of a WIF encoded private key for the network and compression,
+// computed encoding the smallest and the biggest payload (version byte, key, compression flag and checksum)
+func WIFLengthBounds(network Network, compressed bool) (min, max int) {
+	payloadLength := 1 + 32 + 4
+	if compressed {
+		payloadLength++
+	}
+	smallest := make([]byte, payloadLength)
+	biggest := make([]byte, payloadLength)
+	smallest[0] = network.WIFVersion()
+	biggest[0] = network.WIFVersion()
+	for i := 1; i < payloadLength; i++ {
+		biggest[i] = 0xff
+	}
+	return len(base58.Encode(smallest)), len(base58.Encode(biggest))
+}
+
+// PublicChecked derivates a public key in compressed or uncompressed format like Public, returning an error for a private key
+// that is not 32 bytes, is zero (whose public key would be the point at infinity) or is not below the curve order
+func PublicChecked(privateKey []byte, compressed bool) ([]byte, error) {
+	if len(privateKey) != 32 {
+		return nil, fmt.Errorf("private key is %d bytes long, must be 32", len(privateKey))
+	}
+	if !isValidKey(new(big.Int).SetBytes(privateKey)) {
+		return nil, errors.New("given key is zero or not below the curve order")
+	}
+	return Public(privateKey, compressed), nil
+}
+
+// Public derivates a public key in compressed or uncompressed format from a private key, which is not validated: see PublicChecked
 func Public(privateKey []byte, compressed bool) (pubKey []byte) {
 	publicKey := derivatePublicKey(privateKey)
 	if compressed {
@@ -115,6 +426,91 @@ func Hashed(pubKey []byte) []byte {
 	return hash
 }
 
+// Address returns the P2PKH address of a 20 bytes public key hash (as returned by Hashed): version byte (0x00 mainnet, 0x6F testnet),
+// hash and the first 4 bytes of its double sha256, base58 encoded
+func Address(pubKeyHash []byte, testnet bool) (string, error) {
+	if len(pubKeyHash) != 20 {
+		return "", fmt.Errorf("public key hash is %d bytes long, must be 20", len(pubKeyHash))
+	}
+	network := MainNet
+	if testnet {
+		network = TestNet
+	}
+	return base58.CheckEncode(pubKeyHash, network.PubKeyHashVersion()), nil
+}
+
+// DecodeAddress returns the 20 bytes public key hash of a P2PKH address and whether it is a testnet address (version 0x6F)
+// or a mainnet one (version 0x00). Invalid base58, a wrong checksum and an unknown version byte give different errors
+func DecodeAddress(address string) (pubKeyHash []byte, testnet bool, err error) {
+	decoded := base58.Decode(address)
+	if len(decoded) == 0 {
+		return nil, false, fmt.Errorf("invalid address %v: not base58", address)
+	}
+	if len(decoded) != 25 {
+		return nil, false, fmt.Errorf("invalid address %v: decoded length %d, must be 25", address, len(decoded))
+	}
+	hashOne := sha256.Sum256(decoded[:21])
+	hashTwo := sha256.Sum256(hashOne[:])
+	if subtle.ConstantTimeCompare(hashTwo[:4], decoded[21:]) != 1 {
+		return nil, false, fmt.Errorf("invalid address %v: wrong checksum", address)
+	}
+	switch decoded[0] {
+	case MainNet.PubKeyHashVersion():
+		return decoded[1:21], false, nil
+	case TestNet.PubKeyHashVersion():
+		return decoded[1:21], true, nil
+	default:
+		return nil, false, fmt.Errorf("invalid address %v: unknown version byte 0x%02x", address, decoded[0])
+	}
+}
+
+// AddressFromPrivate returns the P2PKH address of a private key, hashing the compressed or uncompressed public key as the flag says:
+// use the same flag as the WIF of the key, since a wallet importing it spends only from the address of that public key format
+func AddressFromPrivate(privKey []byte, compressed bool, testnet bool) (string, error) {
+	if len(privKey) != 32 || !isValidKey(new(big.Int).SetBytes(privKey)) {
+		return "", errors.New("given key is not acceptable as private key")
+	}
+	return Address(Hashed(Public(privKey, compressed)), testnet)
+}
+
+// SegwitAddress returns the native SegWit (P2WPKH) address of a 20 bytes public key hash (as returned by Hashed):
+// the hash is the version 0 witness program, bech32 encoded with human readable part "bc" (mainnet) or "tb" (testnet)
+func SegwitAddress(pubKeyHash []byte, testnet bool) (string, error) {
+	if len(pubKeyHash) != 20 {
+		return "", fmt.Errorf("public key hash is %d bytes long, must be 20", len(pubKeyHash))
+	}
+	network := MainNet
+	if testnet {
+		network = TestNet
+	}
+	return segwit.Encode(network.Bech32HRP(), 0, pubKeyHash)
+}
+
+// NestedSegwitAddress returns the P2SH-P2WPKH (nested SegWit) address of a 20 bytes public key hash (as returned by Hashed):
+// the witness program script 0x0014 || hash is hashed like a public key and base58 encoded with the P2SH version byte (0x05 mainnet, 0xC4 testnet)
+func NestedSegwitAddress(pubKeyHash []byte, testnet bool) (string, error) {
+	if len(pubKeyHash) != 20 {
+		return "", fmt.Errorf("public key hash is %d bytes long, must be 20", len(pubKeyHash))
+	}
+	network := MainNet
+	if testnet {
+		network = TestNet
+	}
+	redeemScript := append([]byte{0x00, 0x14}, pubKeyHash...)
+	return base58.CheckEncode(Hashed(redeemScript), network.ScriptHashVersion()), nil
+}
+
+// VerifyHashers checks that sha256 and ripemd160 give the expected hash160 of a known public key
+// Reference: https://en.bitcoin.it/wiki/Technical_background_of_version_1_Bitcoin_addresses
+func VerifyHashers() error {
+	pubKey, _ := hex.DecodeString("0250863ad64a87ae8a2fe83c1af1a8403cb53f53e486d8511dad8a04887e5b2352")
+	expected := "f54a5851e9372b87810a8e60cdd2e7cfd80b6e31"
+	if actual := hex.EncodeToString(Hashed(pubKey)); actual != expected {
+		return fmt.Errorf("hash160 self test failed: got %v, expected %v", actual, expected)
+	}
+	return nil
+}
+
 func derivatePublicKey(key []byte) ecdsa.PublicKey {
 	bigNumberKey := new(big.Int)
 	bigNumberKey.SetBytes(key)
@@ -128,7 +524,7 @@ func derivatePublicKey(key []byte) ecdsa.PublicKey {
 }
 
 func toCompressedBytes(pubK ecdsa.PublicKey) (compressedPubKey []byte) {
-	byteX := pubK.X.Bytes()
+	byteX := padKey(pubK.X.Bytes())
 	//byteY := pubK.Y.Bytes()
 	yIsEven := isEven(pubK.Y) //O means X is even, 1 means X is odd
 	compressedPubKey = []byte{}
@@ -143,8 +539,8 @@ func toCompressedBytes(pubK ecdsa.PublicKey) (compressedPubKey []byte) {
 }
 
 func toUncompressedBytes(pubK ecdsa.PublicKey) (uncompressedPubKey []byte) {
-	byteX := pubK.X.Bytes()
-	byteY := pubK.Y.Bytes()
+	byteX := padKey(pubK.X.Bytes())
+	byteY := padKey(pubK.Y.Bytes())
 	//Append 0x04 X and Y to build public key
 	uncompressedPubKey = []byte{0x04}
 	uncompressedPubKey = append(uncompressedPubKey, byteX...)
@@ -152,6 +548,16 @@ func toUncompressedBytes(pubK ecdsa.PublicKey) (uncompressedPubKey []byte) {
 	return uncompressedPubKey
 }
 
+// padKey left-pads with zeros a key (or a coordinate) to 32 bytes, restoring the leading zero bytes dropped by big.Int.Bytes()
+func padKey(key []byte) []byte {
+	if len(key) >= 32 {
+		return key
+	}
+	padded := make([]byte, 32)
+	copy(padded[32-len(key):], key)
+	return padded
+}
+
 func isValidKey(keyNum *big.Int) bool {
 	var notTooSmall bool
 	var notTooBig bool
@@ -177,8 +583,48 @@ func isEven(num *big.Int) (even bool) {
 	return
 }
 
-// Mnemonic generates a mnemonic from a byte array
+// Mnemonic generates a mnemonic from a byte array of 16, 20, 24, 28 or 32 bytes, the entropy lengths BIP39 accepts
 func Mnemonic(seed []byte) (string, error) {
+	wordListMutex.RLock()
+	defer wordListMutex.RUnlock()
+	return newMnemonic(seed)
+}
+
+// mnemonicLanguages maps the languages accepted by MnemonicWithLanguage to their BIP39 wordlists
+var mnemonicLanguages = map[string][]string{
+	"english":             wordlists.English,
+	"japanese":            wordlists.Japanese,
+	"spanish":             wordlists.Spanish,
+	"french":              wordlists.French,
+	"italian":             wordlists.Italian,
+	"korean":              wordlists.Korean,
+	"chinese_simplified":  wordlists.ChineseSimplified,
+	"chinese_traditional": wordlists.ChineseTraditional,
+}
+
+// wordListMutex guards the wordlist of the bip39 package, which is global: MnemonicWithLanguage switches it while holding the lock
+var wordListMutex sync.RWMutex
+
+// MnemonicWithLanguage generates a mnemonic from a byte array like Mnemonic, with the words of the BIP39 wordlist of a language
+// (english, japanese, spanish, french, italian, korean, chinese_simplified or chinese_traditional)
+func MnemonicWithLanguage(seed []byte, language string) (string, error) {
+	list, ok := mnemonicLanguages[strings.ToLower(language)]
+	if !ok {
+		return "", fmt.Errorf("unknown mnemonic language %v", language)
+	}
+	wordListMutex.Lock()
+	defer wordListMutex.Unlock()
+	bip39.SetWordList(list)
+	defer bip39.SetWordList(wordlists.English)
+	return newMnemonic(seed)
+}
+
+func newMnemonic(seed []byte) (string, error) {
+	switch len(seed) {
+	case 16, 20, 24, 28, 32:
+	default:
+		return "", fmt.Errorf("cannot generate mnemonic from %d bytes, entropy must be 16, 20, 24, 28 or 32 bytes", len(seed))
+	}
 	mnemonic, err := bip39.NewMnemonic(seed)
 	if err != nil {
 		return "", fmt.Errorf("cennot generate mnemonic: %v", err)
@@ -186,34 +632,145 @@ func Mnemonic(seed []byte) (string, error) {
 	return mnemonic, nil
 }
 
+// FromMnemonic returns the 64 bytes BIP39 seed of a mnemonic and passphrase, the input of the BIP32 master key of a wallet.
+// The mnemonic checksum is validated first, since a mistyped word would silently give another wallet
+func FromMnemonic(mnemonic string, passphrase string) (seed []byte, err error) {
+	wordListMutex.RLock()
+	defer wordListMutex.RUnlock()
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, errors.New("invalid mnemonic: unknown words, wrong number of words or wrong checksum")
+	}
+	return bip39.NewSeed(mnemonic, passphrase), nil
+}
+
+// MnemonicStrength validates a BIP39 mnemonic and returns its number of words and the bits of entropy they encode (128 to 256)
+func MnemonicStrength(mnemonic string) (words int, entropyBits int, err error) {
+	wordListMutex.RLock()
+	defer wordListMutex.RUnlock()
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return 0, 0, errors.New("invalid mnemonic: unknown words, wrong number of words or wrong checksum")
+	}
+	words = len(strings.Fields(mnemonic))
+	// every word is 11 bits, one bit every 33 is checksum
+	entropyBits = words * 11 * 32 / 33
+	return words, entropyBits, nil
+}
+
+// KeyToMnemonic returns the 24 words BIP39 mnemonic whose entropy is the private key itself, a human friendly backup of a single key.
+// This is not a wallet seed: a wallet importing these words would run them through PBKDF2 and BIP32 and derive unrelated keys,
+// only KeyFromMnemonic gives the key back
+func KeyToMnemonic(privKey []byte) (string, error) {
+	if len(privKey) != 32 || !isValidKey(new(big.Int).SetBytes(privKey)) {
+		return "", errors.New("given key is not acceptable as private key")
+	}
+	return Mnemonic(privKey)
+}
+
+// KeyFromMnemonic returns the private key backed up by KeyToMnemonic, i.e. the entropy of a 24 words BIP39 mnemonic.
+// The words are not hashed into a seed and no HD derivation takes place, unlike a wallet seed mnemonic
+func KeyFromMnemonic(mnemonic string) ([]byte, error) {
+	words, _, err := MnemonicStrength(mnemonic)
+	if err != nil {
+		return nil, err
+	}
+	if words != 24 {
+		return nil, fmt.Errorf("mnemonic has %d words, a private key takes 24", words)
+	}
+	wordListMutex.RLock()
+	entropy, err := bip39.EntropyFromMnemonic(strings.Join(strings.Fields(mnemonic), " "))
+	wordListMutex.RUnlock()
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode mnemonic due to %v", err)
+	}
+	key := padKey(entropy)
+	if !isValidKey(new(big.Int).SetBytes(key)) {
+		return nil, errors.New("mnemonic represents a number not acceptable as private key")
+	}
+	return key, nil
+}
+
+// CombineMnemonicHalves joins the words of two parts of a BIP39 mnemonic, kept in different places, and checks the result is a valid mnemonic.
+// This is not secret sharing: each half reveals its words, and with half of a 24 words mnemonic known the remaining 128 bits
+// are the only protection left, so each place must be as safe as if it held the whole mnemonic
+func CombineMnemonicHalves(first, second string) (string, error) {
+	firstWords := strings.Fields(first)
+	secondWords := strings.Fields(second)
+	if len(firstWords) == 0 || len(secondWords) == 0 {
+		return "", errors.New("both halves must have at least a word")
+	}
+	mnemonic := strings.Join(append(firstWords, secondWords...), " ")
+	if _, _, err := MnemonicStrength(mnemonic); err != nil {
+		return "", fmt.Errorf("cannot combine halves: %v", err)
+	}
+	return mnemonic, nil
+}
+
 func coinflipsKey(sequence string) ([]byte, error) {
+	for i := 0; i < len(sequence); i++ {
+		if c := sequence[i]; c != '0' && c != '1' {
+			return nil, fmt.Errorf("char %q at position %d is not a coin flip, must be 0 or 1", c, i)
+		}
+	}
 	bi := new(big.Int)
 	bi, ok := bi.SetString(sequence, 2)
 	if !ok {
 		return nil, fmt.Errorf("big.Int.SetString return false for sequence %v", sequence)
 	}
+	if bi.Sign() == 0 {
+		return nil, errors.New("input sequence represents zero (all flips are 0), please reflip")
+	}
 	if !isValidKey(bi) {
 		return nil, errors.New("input sequence represents a number not acceptable as private key")
 	}
-	return bi.Bytes(), nil
+	return padKey(bi.Bytes()), nil
 }
 
-func diceKey(sequence string) ([]byte, error) {
-	basesix := ""
-	for _, c := range []byte(sequence) {
-		n, err := strconv.ParseInt(string(c), 10, 8)
-		if err != nil {
-			return nil, fmt.Errorf("problems with char %c due to: %v", c, err)
+func hexKey(sequence string) ([]byte, error) {
+	for i := 0; i < len(sequence); i++ {
+		if d, ok := digitValue(sequence[i]); !ok || d >= 16 {
+			return nil, fmt.Errorf("char %c at position %d is not a valid hex char", sequence[i], i)
 		}
-		basesix += strconv.Itoa(int(n - 1))
 	}
 	bi := new(big.Int)
-	bi, ok := bi.SetString(basesix, 6)
+	bi, ok := bi.SetString(sequence, 16)
 	if !ok {
-		return nil, fmt.Errorf("big.Int.SetString return false for sequence %v", basesix)
+		return nil, fmt.Errorf("big.Int.SetString return false for sequence %v", sequence)
+	}
+	if bi.Sign() == 0 {
+		return nil, errors.New("input sequence represents zero (all chars are 0), which is not a private key")
 	}
 	if !isValidKey(bi) {
 		return nil, errors.New("input sequence represents a number not acceptable as private key")
 	}
-	return bi.Bytes(), nil
+	return padKey(bi.Bytes()), nil
+}
+
+func diceKey(sequence string) ([]byte, error) {
+	return diceKeyFaces(sequence, '1')
+}
+
+// diceKeyFaces reads a sequence of dice rolls whose lowest face is labeled low ('1' or '0')
+func diceKeyFaces(sequence string, low byte) ([]byte, error) {
+	basesix := make([]byte, len(sequence))
+	for i := 0; i < len(sequence); i++ {
+		c := sequence[i]
+		if c < low || c > low+5 {
+			return nil, fmt.Errorf("char %q at position %d is not a dice roll, must be %c-%c", c, i, low, low+5)
+		}
+		basesix[i] = c - low + '0'
+	}
+	bi := new(big.Int)
+	bi, ok := bi.SetString(string(basesix), 6)
+	if !ok {
+		return nil, fmt.Errorf("big.Int.SetString return false for sequence %s", basesix)
+	}
+	// 6^99-1 (all dice at the highest face) is below the curve order, so only zero (all dice at the lowest face)
+	// is out of range for a 99 dice sequence; the order check covers longer sequences
+	if bi.Sign() == 0 {
+		return nil, fmt.Errorf("input sequence represents zero (all dice are %c), please reroll", low)
+	}
+	if bi.Cmp(maxValueForKey) > 0 {
+		return nil, errors.New("input sequence represents a number above the curve order, please reroll")
+	}
+	return padKey(bi.Bytes()), nil
 }
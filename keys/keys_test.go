@@ -1,14 +1,20 @@
 package keys
 
 import (
+	"bytes"
 	"encoding/hex"
+	"fmt"
 	"math"
 	"math/big"
 	"math/rand"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/tyler-smith/go-bip39/wordlists"
 )
 
 /*
@@ -19,7 +25,7 @@ func TestDecodeUncompressedWIF_1(t *testing.T) {
 	//http://gobittest.appspot.com/PrivateKey
 	encodedKey := "5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ"
 	privateKey := strings.ToLower("0C28FCA386C7A227600B2FE50B7CAE11EC86D3BF1FBE471BE89827E19D72AA1D")
-	decoded, compressed, err := PrivateFromWIF(encodedKey)
+	decoded, compressed, _, err := PrivateFromWIF(encodedKey)
 	if err != nil {
 		t.Errorf("Failed because: %v", err)
 	}
@@ -35,7 +41,7 @@ func TestDecodeUncompressedWIF_2(t *testing.T) {
 	//http://gobittest.appspot.com/PrivateKey
 	encodedKey := "5JLM1u1wmYBzPaHxr2fEM5cczS9oqeiVfCSXzDZdEVxZraogexk"
 	privateKey := strings.ToLower("4440CD90151432BC082C6925A4A8D4CCFF2065017E9224D16563182C9AD8A7AA")
-	decoded, compressed, err := PrivateFromWIF(encodedKey)
+	decoded, compressed, _, err := PrivateFromWIF(encodedKey)
 	if err != nil {
 		t.Errorf("Failed because: %v", err)
 	}
@@ -51,7 +57,7 @@ func TestDecodeUncompressedWIF_3(t *testing.T) {
 	//http://gobittest.appspot.com/PrivateKey
 	encodedKey := "5JkH4Qek122o4Sz6y4HEXokPvrprfcpEo84BfZxKNZse5zMeAoA"
 	privateKey := strings.ToLower("7A97DA2C6F4BC73D2B330F2634975D6485C7294AD95F33ACC007C5BC5CB1DC5C")
-	decoded, compressed, err := PrivateFromWIF(encodedKey)
+	decoded, compressed, _, err := PrivateFromWIF(encodedKey)
 	if err != nil {
 		t.Errorf("Failed because: %v", err)
 	}
@@ -67,7 +73,7 @@ func TestDecodeCompressedWIF_1(t *testing.T) {
 	//http://gobittest.appspot.com/PrivateKey
 	encodedKey := "KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617"
 	privateKey := strings.ToLower("0C28FCA386C7A227600B2FE50B7CAE11EC86D3BF1FBE471BE89827E19D72AA1D")
-	decoded, compressed, err := PrivateFromWIF(encodedKey)
+	decoded, compressed, _, err := PrivateFromWIF(encodedKey)
 	if err != nil {
 		t.Errorf("Failed because: %v", err)
 	}
@@ -83,7 +89,7 @@ func TestDecodeCompressedWIF_2(t *testing.T) {
 	//http://gobittest.appspot.com/PrivateKey
 	encodedKey := "KyWPLxbAjafWJdwsVQKjudNDrW1sjjU9VbVhmYSzvapG1n8giy9c"
 	privateKey := strings.ToLower("4440CD90151432BC082C6925A4A8D4CCFF2065017E9224D16563182C9AD8A7AA")
-	decoded, compressed, err := PrivateFromWIF(encodedKey)
+	decoded, compressed, _, err := PrivateFromWIF(encodedKey)
 	if err != nil {
 		t.Errorf("Failed because: %v", err)
 	}
@@ -99,7 +105,7 @@ func TestDecodeCompressedWIF_3(t *testing.T) {
 	//http://gobittest.appspot.com/PrivateKey
 	encodedKey := "L1L1t3Pao5YvJDh3LRUeiyLYCivEDT5Vta945ETA6C6WgswTeobf"
 	privateKey := strings.ToLower("7A97DA2C6F4BC73D2B330F2634975D6485C7294AD95F33ACC007C5BC5CB1DC5C")
-	decoded, compressed, err := PrivateFromWIF(encodedKey)
+	decoded, compressed, _, err := PrivateFromWIF(encodedKey)
 	if err != nil {
 		t.Errorf("Failed because: %v", err)
 	}
@@ -291,7 +297,7 @@ func TestFromDiceSequenceRandom(t *testing.T) {
 	random := rand.New(source)
 	for i := 0; i < 10; i++ {
 		sequence := ""
-		for j := 0; j < diceSeqRequiredLength; j++ {
+		for j := 0; j < DiceSeqRequiredLength; j++ {
 			c := int(math.Round(random.Float64()*5.0) + 1)
 			sequence += strconv.Itoa(c)
 		}
@@ -310,7 +316,7 @@ func TestFromCoinflipSequenceRandom(t *testing.T) {
 	random := rand.New(source)
 	for i := 0; i < 10; i++ {
 		sequence := ""
-		for j := 0; j < coinflipSeqRequiredLength; j++ {
+		for j := 0; j < CoinflipSeqRequiredLength; j++ {
 			c := int(math.Round(random.Float64()))
 			sequence += strconv.Itoa(c)
 		}
@@ -331,4 +337,951 @@ func TestMnemonic(t *testing.T) {
 		t.Errorf("Failed due to %v", err)
 	}
 	Mnemonic(privKeyByte)
+	_, err = Mnemonic(privKeyByte[:31])
+	expected := "cannot generate mnemonic from 31 bytes, entropy must be 16, 20, 24, 28 or 32 bytes"
+	if err == nil || err.Error() != expected {
+		t.Errorf("31 bytes should return error %q, got %v", expected, err)
+	}
+}
+
+func TestFromBaseNSequence(t *testing.T) {
+	privKeyHexString := "4440cd90151432bc082c6925a4a8d4ccff2065017e9224d16563182c9ad8a7aa"
+	bn := new(big.Int)
+	bn.SetString(privKeyHexString, 16)
+	for _, base := range []int{2, 6, 10, 16, 36} {
+		sequence := strings.Repeat("0", 100) + bn.Text(base)
+		key, err := FromBaseNSequence(sequence, base)
+		if err != nil {
+			t.Errorf("cannot read base %d sequence due to %v", base, err)
+			continue
+		}
+		if hex.EncodeToString(key) != privKeyHexString {
+			t.Errorf("base %d sequence decoded to %x instead of %v", base, key, privKeyHexString)
+		}
+	}
+}
+
+func TestFromBaseNSequenceErrors(t *testing.T) {
+	invalid := []struct {
+		sequence string
+		base     int
+	}{
+		{"", 10},
+		{"123", 1},
+		{"123", 37},
+		{"1201", 2},
+		{"12g", 16},
+		{strings.Repeat("0", MaxSequenceLength) + "1", 2},
+		{strings.Repeat("0", 99), 6},
+		{strings.Repeat("f", 65), 16},
+	}
+	for _, v := range invalid {
+		if _, err := FromBaseNSequence(v.sequence, v.base); err == nil {
+			t.Errorf("base %d sequence %v should be rejected", v.base, v.sequence)
+		}
+	}
+}
+
+func BenchmarkFromBaseNSequence(b *testing.B) {
+	sequence := strings.Repeat("0", MaxSequenceLength-64) + strings.Repeat("7", 64)
+	for i := 0; i < b.N; i++ {
+		if _, err := FromBaseNSequence(sequence, 16); err != nil {
+			b.Fatalf("cannot read sequence due to %v", err)
+		}
+	}
+}
+
+func TestPadHexKey(t *testing.T) {
+	// Input, expected 32 bytes key
+	hexKeys := [][]string{
+		[]string{"4440CD90151432BC082C6925A4A8D4CCFF2065017E9224D16563182C9AD8A7AA", "4440cd90151432bc082c6925a4a8d4ccff2065017e9224d16563182c9ad8a7aa"},
+		[]string{"cd90151432bc082c6925a4a8d4ccff2065017e9224d16563182c9ad8a7aa", "0000cd90151432bc082c6925a4a8d4ccff2065017e9224d16563182c9ad8a7aa"},
+		[]string{"d90151432bc082c6925a4a8d4ccff2065017e9224d16563182c9ad8a7aa", "00000d90151432bc082c6925a4a8d4ccff2065017e9224d16563182c9ad8a7aa"},
+		[]string{"1", "0000000000000000000000000000000000000000000000000000000000000001"},
+	}
+	for _, v := range hexKeys {
+		key, err := PadHexKey(v[0])
+		if err != nil {
+			t.Errorf("cannot pad %v due to %v", v[0], err)
+			continue
+		}
+		if len(key) != 32 || hex.EncodeToString(key) != v[1] {
+			t.Errorf("key %v should be padded to %v but is %x", v[0], v[1], key)
+		}
+	}
+	invalid := []string{"", "0", "4440CD90151432BC082C6925A4A8D4CCFF2065017E9224D16563182C9AD8A7AA0", "4440CD9015143Z", "0x4440", "FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141"}
+	for _, v := range invalid {
+		if _, err := PadHexKey(v); err == nil {
+			t.Errorf("hex key %v should be rejected", v)
+		}
+	}
+}
+
+func TestWIFLengthBounds(t *testing.T) {
+	wifs := []string{"5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ", "KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617", "L1L1t3Pao5YvJDh3LRUeiyLYCivEDT5Vta945ETA6C6WgswTeobf"}
+	for _, wif := range wifs {
+		_, compressed, _, err := PrivateFromWIF(wif)
+		if err != nil {
+			t.Errorf("Failed because: %v", err)
+		}
+		min, max := WIFLengthBounds(MainNet, compressed)
+		if len(wif) < min || len(wif) > max {
+			t.Errorf("length %d of %v is not between %d and %d", len(wif), wif, min, max)
+		}
+	}
+	if min, max := WIFLengthBounds(MainNet, false); min != 51 || max != 51 {
+		t.Errorf("uncompressed WIF should be 51 chars, got %d-%d", min, max)
+	}
+	if min, max := WIFLengthBounds(TestNet, true); min != 52 || max != 52 {
+		t.Errorf("compressed testnet WIF should be 52 chars, got %d-%d", min, max)
+	}
+}
+
+func TestMnemonicStrength(t *testing.T) {
+	for _, size := range []int{16, 20, 24, 28, 32} {
+		entropy := make([]byte, size)
+		for i := range entropy {
+			entropy[i] = byte(i * 7)
+		}
+		mnemonic, err := Mnemonic(entropy)
+		if err != nil {
+			t.Errorf("cannot generate mnemonic due to %v", err)
+		}
+		words, bits, err := MnemonicStrength(mnemonic)
+		if err != nil {
+			t.Errorf("cannot get strength of %v due to %v", mnemonic, err)
+		}
+		if words != size*3/4 || bits != size*8 {
+			t.Errorf("%d bytes mnemonic should have %d words and %d bits, got %d words and %d bits", size, size*3/4, size*8, words, bits)
+		}
+	}
+	invalid := []string{"", "hello world", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon"}
+	for _, v := range invalid {
+		if _, _, err := MnemonicStrength(v); err == nil {
+			t.Errorf("mnemonic %v should be rejected", v)
+		}
+	}
+}
+
+func TestVerifyHashers(t *testing.T) {
+	if err := VerifyHashers(); err != nil {
+		t.Errorf("hashers self test failed: %v", err)
+	}
+}
+
+func TestFromSeedBytes(t *testing.T) {
+	// Seed, expected key
+	expected := [][]string{
+		// shorter seeds are stretched with sha256
+		[]string{"000102030405060708090a0b0c0d0e0f", "be45cb2605bf36bebde684841a28f0fd43c69850a3dce5fedba69928ee3a8991"},
+		[]string{"ffffffffffffffffffffffffffffffffffffffffffffffff", "44a5f7891570e5631e8c91c85186e6633f4ab5364f644040b2a00126a07985b6"},
+		[]string{"00000000000000000000000000000000", "374708fff7719dd5979ec875d56cd2286f6d3cf7ec317a3b25632aab28ec37bb"},
+		[]string{"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140", "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140"},
+		[]string{"0000000000000000000000000000000000000000000000000000000000000001", "0000000000000000000000000000000000000000000000000000000000000001"},
+	}
+	for _, v := range expected {
+		seed, _ := hex.DecodeString(v[0])
+		key, err := FromSeedBytes(seed)
+		if err != nil {
+			t.Errorf("cannot get key from seed %v due to %v", v[0], err)
+			continue
+		}
+		if hex.EncodeToString(key) != v[1] {
+			t.Errorf("key from seed %v should be %v but is %x", v[0], v[1], key)
+		}
+	}
+	wrong := []string{
+		"",
+		"000102030405060708090a0b0c0d0e",
+		"000102030405060708090a0b0c0d0e0f000102030405060708090a0b0c0d0e0f00",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141",
+	}
+	for _, w := range wrong {
+		seed, _ := hex.DecodeString(w)
+		if _, err := FromSeedBytes(seed); err == nil {
+			t.Errorf("seed %v should not be accepted", w)
+		}
+	}
+	// a 128 bits seed must not give a key below 2^128
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	key, _ := FromSeedBytes(seed)
+	if new(big.Int).SetBytes(key).BitLen() <= 128 {
+		t.Errorf("key %x from a 16 bytes seed is below 2^128", key)
+	}
+}
+
+func TestFromHexSequence(t *testing.T) {
+	key, err := FromHexSequence("0C28FCA386C7A227600B2FE50B7CAE11EC86D3BF1FBE471BE89827E19D72AA1D")
+	if err != nil {
+		t.Errorf("failed due to %v\n", err)
+	}
+	if hex.EncodeToString(key) != "0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d" {
+		t.Errorf("unexpected key %x", key)
+	}
+	key, err = FromHexSequence("00000000000000000000000000000000000000000000000000000000000000ff")
+	if err != nil || len(key) != 32 {
+		t.Errorf("key with leading zeros should be 32 bytes, got %x %v", key, err)
+	}
+	_, err = FromHexSequence("0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aaxd")
+	if err == nil || !strings.Contains(err.Error(), "char x at position 62") {
+		t.Errorf("error should name the offending char, got %v", err)
+	}
+	wrong := []string{
+		"",
+		"0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa",
+		"0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1g",
+		"+c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141",
+	}
+	for _, w := range wrong {
+		if _, err := FromHexSequence(w); err == nil {
+			t.Errorf("sequence %v should not be accepted", w)
+		}
+	}
+}
+
+func FuzzPrivateFromWIF(f *testing.F) {
+	for _, seed := range WIFFuzzSeeds() {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, wif string) {
+		key, _, _, err := PrivateFromWIF(wif)
+		if err == nil && len(key) == 0 {
+			t.Errorf("WIF %q decoded to an empty key without error", wif)
+		}
+	})
+}
+
+func FuzzFromDiceSequence(f *testing.F) {
+	for _, seed := range SequenceFuzzSeeds() {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, sequence string) {
+		if key, err := FromDiceSequence(sequence); err == nil && len(key) == 0 {
+			t.Errorf("sequence %q returned an empty key without error", sequence)
+		}
+	})
+}
+
+func FuzzFromCoinflipSequence(f *testing.F) {
+	for _, seed := range SequenceFuzzSeeds() {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, sequence string) {
+		if key, err := FromCoinflipSequence(sequence); err == nil && len(key) == 0 {
+			t.Errorf("sequence %q returned an empty key without error", sequence)
+		}
+	})
+}
+
+func FuzzFromHexSequence(f *testing.F) {
+	for _, seed := range SequenceFuzzSeeds() {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, sequence string) {
+		if key, err := FromHexSequence(sequence); err == nil && len(key) == 0 {
+			t.Errorf("sequence %q returned an empty key without error", sequence)
+		}
+	})
+}
+
+func TestDetectDiceIndexing(t *testing.T) {
+	// Sequence, expected indexing (empty if an error is expected)
+	expected := [][]string{
+		[]string{"1234566", DiceIndexingOneBased},
+		[]string{"0123455", DiceIndexingZeroBased},
+		[]string{"1234554321", DiceIndexingAmbiguous},
+		[]string{"0123456", ""},
+		[]string{"1234567", ""},
+		[]string{"12a456", ""},
+		[]string{"", ""},
+	}
+	for _, v := range expected {
+		indexing, err := DetectDiceIndexing(v[0])
+		if v[1] == "" {
+			if err == nil {
+				t.Errorf("sequence %v should return an error", v[0])
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+		}
+		if indexing != v[1] {
+			t.Errorf("indexing of %v should be %v but is %v", v[0], v[1], indexing)
+		}
+	}
+}
+
+func TestFromDiceSequenceBounds(t *testing.T) {
+	// the biggest 99 dice value, 6^99-1, is below the curve order and must be accepted
+	key, err := FromDiceSequence(strings.Repeat("6", DiceSeqRequiredLength))
+	if err != nil {
+		t.Errorf("all sixes should be accepted but failed due to %v", err)
+	}
+	expected := "f0bb8a1bbde9163b9e053e8f918bf8e4d34034d7ffffffffffffffffffffffff"
+	if hex.EncodeToString(key) != expected {
+		t.Errorf("all sixes should give %v but gave %x", expected, key)
+	}
+	_, err = FromDiceSequence(strings.Repeat("1", DiceSeqRequiredLength))
+	if err == nil || !strings.Contains(err.Error(), "reroll") {
+		t.Errorf("all ones should ask to reroll, got %v", err)
+	}
+}
+
+func TestFromDiceSequenceInvalidChars(t *testing.T) {
+	// Offending char, expected position in the error
+	expected := [][]string{
+		[]string{"0", "10"},
+		[]string{"7", "50"},
+		[]string{"x", "98"},
+	}
+	for _, v := range expected {
+		pos, _ := strconv.Atoi(v[1])
+		sequence := strings.Repeat("3", pos) + v[0] + strings.Repeat("3", DiceSeqRequiredLength-pos-1)
+		_, err := FromDiceSequence(sequence)
+		if err == nil || !strings.Contains(err.Error(), "position "+v[1]) || !strings.Contains(err.Error(), "'"+v[0]+"'") {
+			t.Errorf("char %v at %v should return an error naming both, got %v", v[0], v[1], err)
+		}
+	}
+}
+
+func TestFromCoinflipSequenceInvalidChars(t *testing.T) {
+	sequence := strings.Repeat("01", 64) + "2" + strings.Repeat("1", 127)
+	_, err := FromCoinflipSequence(sequence)
+	if err == nil || !strings.Contains(err.Error(), "position 128") || !strings.Contains(err.Error(), "'2'") {
+		t.Errorf("stray 2 at position 128 should return an error naming both, got %v", err)
+	}
+}
+
+func TestFromDiceSessions(t *testing.T) {
+	rolls := strings.Repeat("123456", 17)
+	expected := "8fd128918b2e29d6dcbfa5b9a118e5c16d60498c7ba107922a8eb6eb1d36c112"
+	for _, sessions := range [][]string{{rolls}, {rolls[:40], rolls[40:]}, {rolls[:1], rolls[1:50], rolls[50:]}} {
+		key, err := FromDiceSessions(sessions)
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+		}
+		if hex.EncodeToString(key) != expected {
+			t.Errorf("sessions %v should give %v but gave %x", sessions, expected, key)
+		}
+	}
+	invalid := [][]string{
+		{rolls[:98]},
+		{rolls[:50], "", rolls[50:]},
+		{rolls[:50], "0" + rolls[50:]},
+		{rolls, "7"},
+		{strings.Repeat("6", MaxSequenceLength), "1"},
+	}
+	for _, sessions := range invalid {
+		if _, err := FromDiceSessions(sessions); err == nil {
+			t.Errorf("sessions %v should return an error", sessions)
+		}
+	}
+}
+
+func TestAddress(t *testing.T) {
+	privKey, _ := hex.DecodeString("0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d")
+	// Compressed, testnet, address
+	expected := [][]string{
+		[]string{"false", "false", "1GAehh7TsJAHuUAeKZcXf5CnwuGuGgyX2S"},
+		[]string{"true", "false", "1LoVGDgRs9hTfTNJNuXKSpywcbdvwRXpmK"},
+		[]string{"true", "true", "n1KSZGmQgB8iSZqv6UVhGkCGUbEdw8Lm3Q"},
+	}
+	for _, v := range expected {
+		address, err := Address(Hashed(Public(privKey, v[0] == "true")), v[1] == "true")
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+		}
+		if address != v[2] {
+			t.Errorf("address should be %v but is %v", v[2], address)
+		}
+	}
+	if _, err := Address(make([]byte, 19), false); err == nil {
+		t.Errorf("19 bytes hash should return an error")
+	}
+	if _, err := Address(make([]byte, 32), false); err == nil {
+		t.Errorf("32 bytes hash should return an error")
+	}
+}
+
+func TestAddressFromPrivate(t *testing.T) {
+	privKey, _ := hex.DecodeString("0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d")
+	// Compressed, testnet, address
+	expected := [][]string{
+		[]string{"false", "false", "1GAehh7TsJAHuUAeKZcXf5CnwuGuGgyX2S"},
+		[]string{"true", "false", "1LoVGDgRs9hTfTNJNuXKSpywcbdvwRXpmK"},
+		[]string{"true", "true", "n1KSZGmQgB8iSZqv6UVhGkCGUbEdw8Lm3Q"},
+	}
+	for _, v := range expected {
+		address, err := AddressFromPrivate(privKey, v[0] == "true", v[1] == "true")
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+		}
+		if address != v[2] {
+			t.Errorf("address should be %v but is %v", v[2], address)
+		}
+	}
+	if _, err := AddressFromPrivate(make([]byte, 32), true, false); err == nil {
+		t.Errorf("zero key should return an error")
+	}
+}
+
+func TestDecodeAddress(t *testing.T) {
+	// Address, testnet, public key hash
+	expected := [][]string{
+		[]string{"1LoVGDgRs9hTfTNJNuXKSpywcbdvwRXpmK", "false", "d9351dcbad5b8f3b8bfa2f2cdc85c28118ca9326"},
+		[]string{"n1KSZGmQgB8iSZqv6UVhGkCGUbEdw8Lm3Q", "true", "d9351dcbad5b8f3b8bfa2f2cdc85c28118ca9326"},
+		[]string{"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", "false", "751e76e8199196d454941c45d1b3a323f1433bd6"},
+	}
+	for _, v := range expected {
+		hash, testnet, err := DecodeAddress(v[0])
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+			continue
+		}
+		if hex.EncodeToString(hash) != v[2] || testnet != (v[1] == "true") {
+			t.Errorf("address %v should decode to %v (testnet %v) but is %x (testnet %v)", v[0], v[2], v[1], hash, testnet)
+		}
+	}
+	// Address, expected error
+	wrong := [][]string{
+		[]string{"1LoVGDgRs9hTfTNJNuXKSpywcbdvwRXpm0", "not base58"},
+		[]string{"1LoVGDgRs9hTfTNJNuXKSpywcbdvwRXpmL", "wrong checksum"},
+		[]string{"3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", "unknown version byte 0x05"},
+		[]string{"1LoVGDgRs9hTfTNJNuXKSp", "decoded length"},
+	}
+	for _, w := range wrong {
+		_, _, err := DecodeAddress(w[0])
+		if err == nil || !strings.Contains(err.Error(), w[1]) {
+			t.Errorf("address %v should return an error containing %q, got %v", w[0], w[1], err)
+		}
+	}
+}
+
+func TestSegwitAddress(t *testing.T) {
+	// https://github.com/bitcoin/bips/blob/master/bip-0173.mediawiki#examples
+	// Public key hash, testnet, address
+	expected := [][]string{
+		[]string{"751e76e8199196d454941c45d1b3a323f1433bd6", "false", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"},
+		[]string{"751e76e8199196d454941c45d1b3a323f1433bd6", "true", "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx"},
+	}
+	for _, v := range expected {
+		pubKeyHash, _ := hex.DecodeString(v[0])
+		address, err := SegwitAddress(pubKeyHash, v[1] == "true")
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+		}
+		if address != v[2] {
+			t.Errorf("address should be %v but is %v", v[2], address)
+		}
+	}
+	if _, err := SegwitAddress(make([]byte, 32), false); err == nil {
+		t.Errorf("32 bytes hash should return an error")
+	}
+}
+
+func TestNestedSegwitAddress(t *testing.T) {
+	// https://github.com/bitcoin/bips/blob/master/bip-0049.mediawiki#test-vectors and compressed public key of private key 1
+	// Public key hash, testnet, address
+	expected := [][]string{
+		[]string{"751e76e8199196d454941c45d1b3a323f1433bd6", "false", "3JvL6Ymt8MVWiCNHC7oWU6nLeHNJKLZGLN"},
+		[]string{"38971f73930f6c141d977ac4fd4a727c854935b3", "true", "2Mww8dCYPUpKHofjgcXcBCEGmniw9CoaiD2"},
+	}
+	for _, v := range expected {
+		pubKeyHash, _ := hex.DecodeString(v[0])
+		address, err := NestedSegwitAddress(pubKeyHash, v[1] == "true")
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+		}
+		if address != v[2] {
+			t.Errorf("address should be %v but is %v", v[2], address)
+		}
+	}
+	if _, err := NestedSegwitAddress(make([]byte, 32), false); err == nil {
+		t.Errorf("32 bytes hash should return an error")
+	}
+}
+
+func TestFromDiceSequenceN(t *testing.T) {
+	// Dice sequence, expected key (empty if an error is expected)
+	expected := [][]string{
+		// 10 rolls, only ~26 bits of entropy but a valid key
+		[]string{"6543216543", "000000000000000000000000000000000000000000000000000000000375c23c"},
+		[]string{strings.Repeat("1", DiceSeqRequiredLength-1) + "2", "0000000000000000000000000000000000000000000000000000000000000001"},
+		[]string{strings.Repeat("1", 120), ""},
+		[]string{strings.Repeat("6", 120), ""},
+		// leading ones are zeros but the sequence is still too long
+		[]string{strings.Repeat("1", MaxSequenceLength) + "2", ""},
+		[]string{"1234567", ""},
+		[]string{"", ""},
+	}
+	for _, v := range expected {
+		key, err := FromDiceSequenceN(v[0])
+		if v[1] == "" {
+			if err == nil {
+				t.Errorf("sequence %q should return an error", v[0])
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+			continue
+		}
+		if hex.EncodeToString(key) != v[1] {
+			t.Errorf("key of %v should be %v but is %x", v[0], v[1], key)
+		}
+	}
+	long := strings.Repeat("1", 10) + strings.Repeat("3", DiceSeqRequiredLength)
+	key, err := FromDiceSequenceN(long)
+	if err != nil {
+		t.Errorf("failed due to %v\n", err)
+	}
+	if fixed, _ := FromDiceSequence(long[10:]); !bytes.Equal(key, fixed) {
+		t.Errorf("leading ones should not change the key, got %x and %x", key, fixed)
+	}
+}
+
+func TestHexToDiceEquivalent(t *testing.T) {
+	// Hex key, dice sequence (empty if an error is expected)
+	expected := [][]string{
+		[]string{"0000000000000000000000000000000000000000000000000000000000000001", strings.Repeat("1", 98) + "2"},
+		[]string{"0000000000000000000000000000000000000000000000000000000000000006", strings.Repeat("1", 97) + "21"},
+		[]string{"f0bb8a1bbde9163b9e053e8f918bf8e4d34034d7ffffffffffffffffffffffff", strings.Repeat("6", 99)},
+		[]string{"f0bb8a1bbde9163b9e053e8f918bf8e4d34034d8000000000000000000000000", ""},
+		[]string{"0000000000000000000000000000000000000000000000000000000000000000", ""},
+		[]string{"0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1", ""},
+	}
+	for _, v := range expected {
+		dice, err := HexToDiceEquivalent(v[0])
+		if v[1] == "" {
+			if err == nil {
+				t.Errorf("key %v should return an error", v[0])
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+		}
+		if dice != v[1] {
+			t.Errorf("dice of %v should be %v but are %v", v[0], v[1], dice)
+		}
+	}
+	dice, err := HexToDiceEquivalent("0C28FCA386C7A227600B2FE50B7CAE11EC86D3BF1FBE471BE89827E19D72AA1D")
+	if err != nil {
+		t.Errorf("failed due to %v\n", err)
+	}
+	key, err := FromDiceSequence(dice)
+	if err != nil || new(big.Int).SetBytes(key).Text(16) != "c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d" {
+		t.Errorf("dice %v should give back the key, got %x %v", dice, key, err)
+	}
+}
+
+func TestSmallKeysArePadded(t *testing.T) {
+	fromCoinflips, err := FromCoinflipSequence(strings.Repeat("0", CoinflipSeqRequiredLength-1) + "1")
+	if err != nil {
+		t.Errorf("failed due to %v\n", err)
+	}
+	fromDice, err := FromDiceSequence(strings.Repeat("1", DiceSeqRequiredLength-1) + "2")
+	if err != nil {
+		t.Errorf("failed due to %v\n", err)
+	}
+	expected := "0000000000000000000000000000000000000000000000000000000000000001"
+	for _, key := range [][]byte{fromCoinflips, fromDice} {
+		if hex.EncodeToString(key) != expected {
+			t.Errorf("key should be %v but is %x", expected, key)
+		}
+		wif, err := ToWIF(key, true)
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+		}
+		if wif != "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn" {
+			t.Errorf("unexpected WIF %v", wif)
+		}
+		decoded, _, _, err := PrivateFromWIF(wif)
+		if err != nil || hex.EncodeToString(decoded) != expected {
+			t.Errorf("WIF %v should decode to %v, got %x %v", wif, expected, decoded, err)
+		}
+	}
+	// an unpadded key is rejected, it must be padded before encoding
+	for _, short := range [][]byte{{0x01}, make([]byte, 31)} {
+		_, err := ToWIF(short, true)
+		if err == nil || err.Error() != fmt.Sprintf("private key is %d bytes long, must be 32", len(short)) {
+			t.Errorf("%d bytes key should return a length error, got %v", len(short), err)
+		}
+	}
+	if _, err := ToWIF(make([]byte, 33), true); err == nil {
+		t.Errorf("33 bytes key should return an error")
+	}
+}
+
+func TestZeroAndOneKeys(t *testing.T) {
+	_, err := FromDiceSequence(strings.Repeat("1", DiceSeqRequiredLength))
+	if err == nil || !strings.Contains(err.Error(), "zero") {
+		t.Errorf("all ones dice should be rejected as zero, got %v", err)
+	}
+	_, err = FromCoinflipSequence(strings.Repeat("0", CoinflipSeqRequiredLength))
+	if err == nil || !strings.Contains(err.Error(), "zero") {
+		t.Errorf("all zeros flips should be rejected as zero, got %v", err)
+	}
+	_, err = FromHexSequence(strings.Repeat("0", HexSeqRequiredLength))
+	if err == nil || !strings.Contains(err.Error(), "zero") {
+		t.Errorf("all zeros hex should be rejected as zero, got %v", err)
+	}
+	// 1 is the smallest valid key, its public key is the generator point
+	key, err := FromHexSequence(strings.Repeat("0", HexSeqRequiredLength-1) + "1")
+	if err != nil {
+		t.Errorf("key 1 should be accepted but failed due to %v", err)
+	}
+	compressed := "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"
+	uncompressed := "0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8"
+	if hex.EncodeToString(Public(key, true)) != compressed {
+		t.Errorf("compressed public key of 1 should be %v but is %x", compressed, Public(key, true))
+	}
+	if hex.EncodeToString(Public(key, false)) != uncompressed {
+		t.Errorf("uncompressed public key of 1 should be %v but is %x", uncompressed, Public(key, false))
+	}
+}
+
+func TestPrivateFromWIFLength(t *testing.T) {
+	for _, wif := range []string{"", "123", "11111"} {
+		_, _, _, err := PrivateFromWIF(wif)
+		if err == nil || !strings.Contains(err.Error(), "too short") {
+			t.Errorf("WIF %q should be rejected as too short, got %v", wif, err)
+		}
+	}
+	_, _, _, err := PrivateFromWIF("KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617KwdMAjGmerYanjeui5SHS7Jkmp")
+	if err == nil || !strings.Contains(err.Error(), "too long") {
+		t.Errorf("long WIF should be rejected as too long, got %v", err)
+	}
+	// 38 bytes with a 0x02 suffix instead of the 0x01 compression flag
+	payload, _ := hex.DecodeString("0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d02")
+	_, _, _, err = PrivateFromWIF(base58.CheckEncode(payload, MainNet.WIFVersion()))
+	if err == nil || !strings.Contains(err.Error(), "compression flag") {
+		t.Errorf("WIF with a 0x02 suffix should be rejected, got %v", err)
+	}
+}
+
+func TestPrivateFromWIFChecksumError(t *testing.T) {
+	wif := "5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTj"
+	_, _, _, err := PrivateFromWIF(wif)
+	if err == nil || !strings.Contains(err.Error(), wif) {
+		t.Errorf("wrong checksum error should name the WIF, got %v", err)
+	}
+}
+
+func TestCombineMnemonicHalves(t *testing.T) {
+	entropy := make([]byte, 32)
+	for i := range entropy {
+		entropy[i] = byte(i)
+	}
+	mnemonic, _ := Mnemonic(entropy)
+	words := strings.Fields(mnemonic)
+	first := strings.Join(words[:12], " ")
+	second := strings.Join(words[12:], " ")
+	combined, err := CombineMnemonicHalves(" "+first+"\n", second)
+	if err != nil {
+		t.Errorf("failed due to %v\n", err)
+	}
+	if combined != mnemonic {
+		t.Errorf("combined mnemonic should be %v but is %v", mnemonic, combined)
+	}
+	wrong := [][]string{
+		[]string{second, first},
+		[]string{first, ""},
+		[]string{first, strings.Join(words[12:23], " ")},
+	}
+	for _, w := range wrong {
+		if _, err := CombineMnemonicHalves(w[0], w[1]); err == nil {
+			t.Errorf("halves %q and %q should return an error", w[0], w[1])
+		}
+	}
+}
+
+func TestToWIFTestnet(t *testing.T) {
+	privKey, _ := hex.DecodeString("0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d")
+	// Compressed, testnet WIF
+	expected := [][]string{
+		[]string{"true", "cMzLdeGd5vEqxB8B6VFQoRopQ3sLAAvEzDAoQgvX54xwofSWj1fx"},
+		[]string{"false", "91gGn1HgSap6CbU12F6z3pJri26xzp7Ay1VW6NHCoEayNXwRpu2"},
+	}
+	for _, v := range expected {
+		wif, err := ToWIFTestnet(privKey, v[0] == "true")
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+		}
+		if wif != v[1] {
+			t.Errorf("testnet WIF should be %v but is %v", v[1], wif)
+		}
+		payload, version, err := base58.CheckDecode(wif)
+		if err != nil || version != 0xEF || !strings.HasPrefix(hex.EncodeToString(payload), hex.EncodeToString(privKey)) {
+			t.Errorf("WIF %v should hold version 0xef and the key, got %x %x %v", wif, version, payload, err)
+		}
+	}
+}
+
+func TestPrivateFromWIFNetwork(t *testing.T) {
+	// WIF, compressed, testnet
+	expected := [][]string{
+		[]string{"KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617", "true", "false"},
+		[]string{"5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ", "false", "false"},
+		[]string{"cMzLdeGd5vEqxB8B6VFQoRopQ3sLAAvEzDAoQgvX54xwofSWj1fx", "true", "true"},
+		[]string{"91gGn1HgSap6CbU12F6z3pJri26xzp7Ay1VW6NHCoEayNXwRpu2", "false", "true"},
+	}
+	for _, v := range expected {
+		key, compressed, testnet, err := PrivateFromWIF(v[0])
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+			continue
+		}
+		if hex.EncodeToString(key) != "0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d" {
+			t.Errorf("unexpected key %x from %v", key, v[0])
+		}
+		if strconv.FormatBool(compressed) != v[1] || strconv.FormatBool(testnet) != v[2] {
+			t.Errorf("%v should be compressed %v testnet %v, got %v %v", v[0], v[1], v[2], compressed, testnet)
+		}
+		wif, _ := ToWIFNetwork(key, compressed, map[bool]Network{false: MainNet, true: TestNet}[testnet])
+		if wif != v[0] {
+			t.Errorf("%v should round trip, got %v", v[0], wif)
+		}
+	}
+	// Litecoin WIF, version 0xB0
+	if _, _, _, err := PrivateFromWIF("T3GHWKgJhGD3t3dPy9fnmNFeo6fbEgKXTQeddMqEXLoZbo2qpnsV"); err == nil {
+		t.Errorf("unknown WIF version should return an error")
+	}
+}
+
+func TestKeyToMnemonic(t *testing.T) {
+	vectors := []string{
+		"0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d",
+		"00000000000000000000000000000000000000000000000000000000000000ff",
+	}
+	for _, k := range vectors {
+		privKey, _ := hex.DecodeString(k)
+		mnemonic, err := KeyToMnemonic(privKey)
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+			continue
+		}
+		if words := len(strings.Fields(mnemonic)); words != 24 {
+			t.Errorf("mnemonic of %v should have 24 words, has %d", k, words)
+		}
+		back, err := KeyFromMnemonic(mnemonic)
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+			continue
+		}
+		if hex.EncodeToString(back) != k {
+			t.Errorf("key should be %v but is %x", k, back)
+		}
+	}
+	if _, err := KeyToMnemonic(make([]byte, 32)); err == nil {
+		t.Errorf("zero key should return an error")
+	}
+	short, _ := Mnemonic(make([]byte, 16))
+	zero, _ := Mnemonic(make([]byte, 32))
+	for _, m := range []string{short, zero, "not a mnemonic"} {
+		if _, err := KeyFromMnemonic(m); err == nil {
+			t.Errorf("mnemonic %q should return an error", m)
+		}
+	}
+}
+
+func TestCoinflipLeadingZeros(t *testing.T) {
+	// 32 zero flips, then the bits of a known key shifted out of its first 4 bytes
+	known, _ := hex.DecodeString("0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d")
+	expected := append(make([]byte, 4), known[4:]...)
+	var sequence strings.Builder
+	for _, b := range expected {
+		sequence.WriteString(fmt.Sprintf("%08b", b))
+	}
+	key, err := FromCoinflipSequence(sequence.String())
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	if len(key) != 32 || !bytes.Equal(key, expected) {
+		t.Errorf("key should be %x but is %x", expected, key)
+	}
+}
+
+func TestFromMnemonic(t *testing.T) {
+	// https://github.com/trezor/python-mnemonic/blob/master/vectors.json, passphrase TREZOR
+	vectors := [][]string{
+		[]string{"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			"c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04"},
+		[]string{"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+			"ac27495480225222079d7be181583751e86f571027b0497b5b5d11218e0a8a13332572917f0f8e5a589620c6f15b11c61dee327651a14c34e18231052e48c069"},
+		[]string{"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art",
+			"bda85446c68413707090a52022edd26a1c9462295029f2e60cd7c4f2bbd3097170af7a4d73245cafa9c3cca8d561a7c3de6f5d4a10be8ed2a5e608d68f92fcc8"},
+		[]string{"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo vote",
+			"dd48c104698c30cfe2b6142103248622fb7bb0ff692eebb00089b32d22484e1613912f0a5b694407be899ffd31ed3992c456cdf60f5d4564b8ba3f05a69890ad"},
+	}
+	for _, v := range vectors {
+		seed, err := FromMnemonic(v[0], "TREZOR")
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+			continue
+		}
+		if hex.EncodeToString(seed) != v[1] {
+			t.Errorf("seed of %v should be %v but is %x", v[0], v[1], seed)
+		}
+	}
+	wrong := []string{
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		"",
+	}
+	for _, m := range wrong {
+		if _, err := FromMnemonic(m, "TREZOR"); err == nil {
+			t.Errorf("mnemonic %q should return an error", m)
+		}
+	}
+}
+
+func TestMnemonicWithLanguage(t *testing.T) {
+	entropy := make([]byte, 16)
+	english, err := MnemonicWithLanguage(entropy, "English")
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	spanish, err := MnemonicWithLanguage(entropy, "spanish")
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	japanese, err := MnemonicWithLanguage(entropy, "japanese")
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	// zero entropy is the first word 11 times, then the checksum word
+	expected := map[string][]string{english: wordlists.English, spanish: wordlists.Spanish, japanese: wordlists.Japanese}
+	for mnemonic, list := range expected {
+		words := strings.Fields(mnemonic)
+		if len(words) != 12 || words[0] != list[0] || words[11] != list[3] {
+			t.Errorf("mnemonic %v does not use its wordlist", mnemonic)
+		}
+	}
+	if english == spanish || english == japanese {
+		t.Errorf("languages should give different mnemonics")
+	}
+	if mnemonic, _ := Mnemonic(entropy); mnemonic != english {
+		t.Errorf("Mnemonic should still use the english wordlist, got %v", mnemonic)
+	}
+	if _, err := MnemonicWithLanguage(entropy, "klingon"); err == nil {
+		t.Errorf("unknown language should return an error")
+	}
+	if _, err := MnemonicWithLanguage(entropy[:15], "spanish"); err == nil {
+		t.Errorf("15 bytes entropy should return an error")
+	}
+	// the wordlist is global, concurrent calls must not see each other's
+	languages := map[string]string{"english": english, "spanish": spanish, "japanese": japanese}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		for language, expected := range languages {
+			wg.Add(1)
+			go func(language, expected string) {
+				defer wg.Done()
+				if mnemonic, _ := MnemonicWithLanguage(entropy, language); mnemonic != expected {
+					t.Errorf("concurrent %v mnemonic should be %v but is %v", language, expected, mnemonic)
+				}
+			}(language, expected)
+		}
+	}
+	wg.Wait()
+}
+
+func TestVerifyWIF(t *testing.T) {
+	// WIF, compressed, testnet
+	expected := [][]string{
+		[]string{"5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ", "false", "false"},
+		[]string{"KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617", "true", "false"},
+		[]string{"cMzLdeGd5vEqxB8B6VFQoRopQ3sLAAvEzDAoQgvX54xwofSWj1fx", "true", "true"},
+	}
+	for _, v := range expected {
+		valid, compressed, testnet, err := VerifyWIF(v[0])
+		if err != nil || !valid {
+			t.Errorf("WIF %v should be valid, got %v", v[0], err)
+			continue
+		}
+		if compressed != (v[1] == "true") || testnet != (v[2] == "true") {
+			t.Errorf("WIF %v should be compressed %v and testnet %v, got %v and %v", v[0], v[1], v[2], compressed, testnet)
+		}
+	}
+	zero, _ := ToWIF(make([]byte, 32), true)
+	order, _ := hex.DecodeString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141")
+	tooBig, _ := ToWIF(order, false)
+	key, _ := hex.DecodeString("0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d")
+	// WIF, expected error
+	wrong := [][]string{
+		[]string{"KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP9861l", "not base58"},
+		[]string{base58.CheckEncode(append(key, 0x01), 0x00), "version 0x00"},
+		[]string{"KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98618", "checksum is wrong"},
+		[]string{zero, "not below the curve order"},
+		[]string{tooBig, "not below the curve order"},
+		[]string{base58.CheckEncode(append(key, 0x02), 0x80), "compression flag"},
+	}
+	for _, w := range wrong {
+		valid, _, _, err := VerifyWIF(w[0])
+		if valid || err == nil || !strings.Contains(err.Error(), w[1]) {
+			t.Errorf("WIF %v should return an error containing %q, got %v", w[0], w[1], err)
+		}
+	}
+}
+
+func TestPublicChecked(t *testing.T) {
+	privKey, _ := hex.DecodeString("0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d")
+	for _, compressed := range []bool{true, false} {
+		pubKey, err := PublicChecked(privKey, compressed)
+		if err != nil || !bytes.Equal(pubKey, Public(privKey, compressed)) {
+			t.Errorf("public key should be %x but is %x (%v)", Public(privKey, compressed), pubKey, err)
+		}
+	}
+	order, _ := hex.DecodeString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141")
+	wrong := [][]byte{make([]byte, 32), order, bytes.Repeat([]byte{0xff}, 32), append([]byte{0x01}, privKey...), privKey[1:]}
+	for _, w := range wrong {
+		if _, err := PublicChecked(w, true); err == nil {
+			t.Errorf("key %x should return an error", w)
+		}
+	}
+}
+
+func TestFromDiceSequenceZeroBased(t *testing.T) {
+	// the same rolls labeled 0-5 and 1-6, expected key
+	expected := [][]string{
+		[]string{strings.Repeat("0", DiceSeqRequiredLength-1) + "1", strings.Repeat("1", DiceSeqRequiredLength-1) + "2", "0000000000000000000000000000000000000000000000000000000000000001"},
+		[]string{strings.Repeat("5", DiceSeqRequiredLength), strings.Repeat("6", DiceSeqRequiredLength), "f0bb8a1bbde9163b9e053e8f918bf8e4d34034d7ffffffffffffffffffffffff"},
+	}
+	for _, v := range expected {
+		zeroBased, err := FromDiceSequenceZeroBased(v[0])
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+			continue
+		}
+		oneBased, err := FromDiceSequence(v[1])
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+			continue
+		}
+		if hex.EncodeToString(zeroBased) != v[2] || hex.EncodeToString(oneBased) != v[2] {
+			t.Errorf("both labelings should give %v, got %x and %x", v[2], zeroBased, oneBased)
+		}
+	}
+	_, err := FromDiceSequenceZeroBased(strings.Repeat("0", DiceSeqRequiredLength))
+	if err == nil || !strings.Contains(err.Error(), "all dice are 0") {
+		t.Errorf("all zeros sequence should be rejected as all dice are 0, got %v", err)
+	}
+	wrong := []string{
+		strings.Repeat("1", DiceSeqRequiredLength-1) + "6",
+		strings.Repeat("1", DiceSeqRequiredLength-1) + "x",
+		strings.Repeat("1", DiceSeqRequiredLength-1),
+	}
+	for _, w := range wrong {
+		if _, err := FromDiceSequenceZeroBased(w); err == nil {
+			t.Errorf("sequence %v should return an error", w)
+		}
+	}
+	if _, err := FromDiceSequence(strings.Repeat("1", DiceSeqRequiredLength-1) + "0"); err == nil {
+		t.Errorf("0 should be rejected by the 1-6 labeling")
+	}
 }
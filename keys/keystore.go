@@ -0,0 +1,159 @@
+package keys
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/crypto/sha3"
+)
+
+// Limits on the key derivation parameters read from a keystore, so that a crafted file cannot make the import run out of memory
+// or run for hours. scrypt needs 128*n*r bytes: the cap is the 256 MiB of the strongest setting wallets write (n 262144, r 8, p 1)
+const (
+	maxScryptN       = 1 << 20
+	maxScryptRP      = 1 << 10
+	maxScryptMemory  = 256 << 20
+	maxPBKDF2Rounds  = 10000000
+	maxKeystoreDKLen = 64
+)
+
+// keystoreJSON is the structure of a version 3 keystore file (Web3 Secret Storage)
+type keystoreJSON struct {
+	Version int `json:"version"`
+	Crypto  struct {
+		Cipher       string `json:"cipher"`
+		CipherText   string `json:"ciphertext"`
+		CipherParams struct {
+			IV string `json:"iv"`
+		} `json:"cipherparams"`
+		KDF       string          `json:"kdf"`
+		KDFParams json.RawMessage `json:"kdfparams"`
+		MAC       string          `json:"mac"`
+	} `json:"crypto"`
+}
+
+type scryptParams struct {
+	DKLen int    `json:"dklen"`
+	N     int    `json:"n"`
+	R     int    `json:"r"`
+	P     int    `json:"p"`
+	Salt  string `json:"salt"`
+}
+
+type pbkdf2Params struct {
+	DKLen int    `json:"dklen"`
+	C     int    `json:"c"`
+	PRF   string `json:"prf"`
+	Salt  string `json:"salt"`
+}
+
+// ImportKeystoreJSON returns the private key stored in a version 3 keystore file (scrypt or pbkdf2, aes-128-ctr).
+// The key derived from the passphrase is checked against the MAC of the file before decrypting, so a wrong passphrase
+// or a corrupted file is reported as an error instead of giving a wrong key
+func ImportKeystoreJSON(data []byte, passphrase string) ([]byte, error) {
+	var keystore keystoreJSON
+	if err := json.Unmarshal(data, &keystore); err != nil {
+		return nil, fmt.Errorf("cannot parse keystore due to %v", err)
+	}
+	if keystore.Version != 3 {
+		return nil, fmt.Errorf("unsupported keystore version %d", keystore.Version)
+	}
+	if keystore.Crypto.Cipher != "aes-128-ctr" {
+		return nil, fmt.Errorf("unsupported keystore cipher %q", keystore.Crypto.Cipher)
+	}
+	cipherText, err := hex.DecodeString(keystore.Crypto.CipherText)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode ciphertext due to %v", err)
+	}
+	iv, err := hex.DecodeString(keystore.Crypto.CipherParams.IV)
+	if err != nil || len(iv) != aes.BlockSize {
+		return nil, errors.New("keystore iv must be 16 bytes hex encoded")
+	}
+	mac, err := hex.DecodeString(keystore.Crypto.MAC)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode mac due to %v", err)
+	}
+	derivedKey, err := keystoreDerivedKey(keystore.Crypto.KDF, keystore.Crypto.KDFParams, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(derivedKey[16:32])
+	hash.Write(cipherText)
+	if subtle.ConstantTimeCompare(hash.Sum(nil), mac) != 1 {
+		return nil, errors.New("keystore mac mismatch: wrong passphrase or corrupted file")
+	}
+	block, err := aes.NewCipher(derivedKey[:16])
+	if err != nil {
+		return nil, fmt.Errorf("cannot create cipher due to %v", err)
+	}
+	key := make([]byte, len(cipherText))
+	cipher.NewCTR(block, iv).XORKeyStream(key, cipherText)
+	if len(key) != 32 || !isValidKey(new(big.Int).SetBytes(key)) {
+		return nil, errors.New("keystore content is not acceptable as private key")
+	}
+	return key, nil
+}
+
+// keystoreDerivedKey runs the key derivation function of a keystore on the passphrase
+func keystoreDerivedKey(kdf string, rawParams json.RawMessage, passphrase string) ([]byte, error) {
+	var derivedKey []byte
+	switch kdf {
+	case "scrypt":
+		var params scryptParams
+		if err := json.Unmarshal(rawParams, &params); err != nil {
+			return nil, fmt.Errorf("cannot parse scrypt parameters due to %v", err)
+		}
+		salt, err := hex.DecodeString(params.Salt)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode salt due to %v", err)
+		}
+		if params.DKLen < 32 || params.DKLen > maxKeystoreDKLen {
+			return nil, fmt.Errorf("derived key length %d must be between 32 and %d", params.DKLen, maxKeystoreDKLen)
+		}
+		if params.N < 2 || params.N > maxScryptN || params.N&(params.N-1) != 0 {
+			return nil, fmt.Errorf("scrypt n %d must be a power of two between 2 and %d", params.N, maxScryptN)
+		}
+		if params.R < 1 || params.P < 1 || params.R > maxScryptRP || params.P > maxScryptRP || params.R*params.P > maxScryptRP {
+			return nil, fmt.Errorf("scrypt r %d and p %d must be positive with r*p at most %d", params.R, params.P, maxScryptRP)
+		}
+		if memory := 128 * int64(params.N) * int64(params.R); memory > maxScryptMemory {
+			return nil, fmt.Errorf("scrypt n %d and r %d need %d MiB, at most %d MiB are allowed", params.N, params.R, memory>>20, maxScryptMemory>>20)
+		}
+		derivedKey, err = scrypt.Key([]byte(passphrase), salt, params.N, params.R, params.P, params.DKLen)
+		if err != nil {
+			return nil, fmt.Errorf("cannot derive key due to %v", err)
+		}
+	case "pbkdf2":
+		var params pbkdf2Params
+		if err := json.Unmarshal(rawParams, &params); err != nil {
+			return nil, fmt.Errorf("cannot parse pbkdf2 parameters due to %v", err)
+		}
+		if params.PRF != "hmac-sha256" {
+			return nil, fmt.Errorf("unsupported pbkdf2 prf %q", params.PRF)
+		}
+		salt, err := hex.DecodeString(params.Salt)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode salt due to %v", err)
+		}
+		if params.DKLen < 32 || params.DKLen > maxKeystoreDKLen {
+			return nil, fmt.Errorf("derived key length %d must be between 32 and %d", params.DKLen, maxKeystoreDKLen)
+		}
+		if params.C < 1 || params.C > maxPBKDF2Rounds {
+			return nil, fmt.Errorf("pbkdf2 iteration count %d must be between 1 and %d", params.C, maxPBKDF2Rounds)
+		}
+		derivedKey = pbkdf2.Key([]byte(passphrase), salt, params.C, params.DKLen, sha256.New)
+	default:
+		return nil, fmt.Errorf("unsupported keystore kdf %q", kdf)
+	}
+	return derivedKey, nil
+}
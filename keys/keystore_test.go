@@ -0,0 +1,63 @@
+package keys
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// keystore test vectors from the Web3 Secret Storage definition, password "testpassword"
+const (
+	keystoreScryptVector = `{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"83dbcc02d8ccb40e466191a123791e0e"},
+"ciphertext":"d172bf743a674da9cdad04534d56926ef8358534d458fffccd4e6ad2fbde479c","kdf":"scrypt",
+"kdfparams":{"dklen":32,"n":262144,"r":1,"p":8,"salt":"ab0c7876052600dd703518d6fc3fe8984592145b591fc8fb5c6d43190334ba19"},
+"mac":"2103ac29920d71da29f15d75b4a16dbe95cfd7ff8faea1056c33131d846e3097"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`
+	keystorePBKDF2Vector = `{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"6087dab2f9fdbbfaddc31a909735c1e6"},
+"ciphertext":"5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46","kdf":"pbkdf2",
+"kdfparams":{"c":262144,"dklen":32,"prf":"hmac-sha256","salt":"ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"},
+"mac":"517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`
+	keystoreVectorKey = "7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d"
+)
+
+func TestImportKeystoreJSON(t *testing.T) {
+	for _, vector := range []string{keystoreScryptVector, keystorePBKDF2Vector} {
+		key, err := ImportKeystoreJSON([]byte(vector), "testpassword")
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+		}
+		if hex.EncodeToString(key) != keystoreVectorKey {
+			t.Errorf("unexpected key %x", key)
+		}
+	}
+}
+
+func TestImportKeystoreJSONErrors(t *testing.T) {
+	if _, err := ImportKeystoreJSON([]byte(keystorePBKDF2Vector), "wrongpassword"); err == nil {
+		t.Errorf("wrong passphrase should return an error")
+	}
+	corrupted := strings.Replace(keystorePBKDF2Vector, "5318b4d5", "5318b4d6", 1)
+	if _, err := ImportKeystoreJSON([]byte(corrupted), "testpassword"); err == nil {
+		t.Errorf("corrupted ciphertext should return an error")
+	}
+	invalid := [][]string{
+		{"not json", "{"},
+		{"wrong version", strings.Replace(keystorePBKDF2Vector, `"version":3`, `"version":1`, 1)},
+		{"unsupported cipher", strings.Replace(keystorePBKDF2Vector, "aes-128-ctr", "aes-128-cbc", 1)},
+		{"unsupported kdf", strings.Replace(keystorePBKDF2Vector, `"kdf":"pbkdf2"`, `"kdf":"argon2"`, 1)},
+		{"unsupported prf", strings.Replace(keystorePBKDF2Vector, "hmac-sha256", "hmac-sha512", 1)},
+		{"bad iv", strings.Replace(keystorePBKDF2Vector, "6087dab2", "6087dab", 1)},
+		{"short dklen", strings.Replace(keystorePBKDF2Vector, `"dklen":32`, `"dklen":16`, 1)},
+		{"huge dklen", strings.Replace(keystorePBKDF2Vector, `"dklen":32`, `"dklen":1073741824`, 1)},
+		{"oversized pbkdf2 rounds", strings.Replace(keystorePBKDF2Vector, `"c":262144`, `"c":2000000000`, 1)},
+		{"oversized scrypt n", strings.Replace(keystoreScryptVector, `"n":262144`, `"n":1073741824`, 1)},
+		{"scrypt n not a power of two", strings.Replace(keystoreScryptVector, `"n":262144`, `"n":262143`, 1)},
+		{"oversized scrypt memory", strings.Replace(strings.Replace(keystoreScryptVector, `"n":262144`, `"n":1048576`, 1), `"r":1,"p":8`, `"r":1024,"p":1`, 1)},
+		{"scrypt memory above 256 MiB", strings.Replace(keystoreScryptVector, `"r":1,"p":8`, `"r":16,"p":1`, 1)},
+		{"oversized scrypt r*p", strings.Replace(keystoreScryptVector, `"r":1,"p":8`, `"r":1024,"p":8`, 1)},
+	}
+	for _, c := range invalid {
+		if _, err := ImportKeystoreJSON([]byte(c[1]), "testpassword"); err == nil {
+			t.Errorf("%v should return an error", c[0])
+		}
+	}
+}
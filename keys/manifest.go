@@ -0,0 +1,112 @@
+package keys
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcutil/base58"
+)
+
+// manifestContent holds the fields of a backup manifest covered by its signature
+type manifestContent struct {
+	Network   string `json:"network"`
+	WIF       string `json:"wif"`
+	Hex       string `json:"hex"`
+	PublicKey string `json:"public_key"`
+	Address   string `json:"address"`
+}
+
+// signedManifest is a backup manifest with the hex DER signature of the sha256 of its compact JSON content
+type signedManifest struct {
+	manifestContent
+	Signature string `json:"signature"`
+}
+
+// SignedManifest returns a JSON backup of a private key (compressed WIF, hex, public key and P2PKH address on the network)
+// signed with the key itself, so that VerifyManifest can tell if any field was altered.
+// The signature covers the sha256 of the compact JSON of all the fields but the signature, in the order they are written
+func SignedManifest(privKey []byte, network Network) (manifest string, err error) {
+	if network.String() == "unknown" {
+		return "", fmt.Errorf("unknown network %d", network)
+	}
+	if len(privKey) != 32 || !isValidKey(new(big.Int).SetBytes(privKey)) {
+		return "", errors.New("given key is not acceptable as private key")
+	}
+	wif, err := ToWIFNetwork(privKey, true, network)
+	if err != nil {
+		return "", err
+	}
+	pubKey := Public(privKey, true)
+	content := manifestContent{
+		Network:   network.String(),
+		WIF:       wif,
+		Hex:       hex.EncodeToString(privKey),
+		PublicKey: hex.EncodeToString(pubKey),
+		Address:   base58.CheckEncode(Hashed(pubKey), network.PubKeyHashVersion()),
+	}
+	digest, err := manifestDigest(content)
+	if err != nil {
+		return "", err
+	}
+	signature, err := SignHash(privKey, digest)
+	if err != nil {
+		return "", err
+	}
+	encoded, err := json.MarshalIndent(signedManifest{content, hex.EncodeToString(signature)}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("cannot encode manifest due to %v", err)
+	}
+	return string(encoded), nil
+}
+
+// VerifyManifest tells if the signature of a manifest made by SignedManifest matches its public key,
+// and if the WIF, public key and address are those of the hex private key it contains
+func VerifyManifest(manifest string) (bool, error) {
+	var signed signedManifest
+	if err := json.Unmarshal([]byte(manifest), &signed); err != nil {
+		return false, fmt.Errorf("cannot parse manifest due to %v", err)
+	}
+	pubKey, err := hex.DecodeString(signed.PublicKey)
+	if err != nil {
+		return false, fmt.Errorf("cannot decode public key due to %v", err)
+	}
+	signature, err := hex.DecodeString(signed.Signature)
+	if err != nil {
+		return false, fmt.Errorf("cannot decode signature due to %v", err)
+	}
+	digest, err := manifestDigest(signed.manifestContent)
+	if err != nil {
+		return false, err
+	}
+	if !VerifyHash(pubKey, digest, signature) {
+		return false, nil
+	}
+	privKey, err := hex.DecodeString(signed.Hex)
+	if err != nil || len(privKey) != 32 {
+		return false, nil
+	}
+	for _, network := range Networks {
+		if network.String() != signed.Network {
+			continue
+		}
+		wif, err := ToWIFNetwork(privKey, true, network)
+		if err != nil || wif != signed.WIF || !bytes.Equal(Public(privKey, true), pubKey) {
+			return false, nil
+		}
+		return signed.Address == base58.CheckEncode(Hashed(pubKey), network.PubKeyHashVersion()), nil
+	}
+	return false, fmt.Errorf("unknown network %v", signed.Network)
+}
+
+func manifestDigest(content manifestContent) ([32]byte, error) {
+	encoded, err := json.Marshal(content)
+	if err != nil {
+		return [32]byte{}, fmt.Errorf("cannot encode manifest content due to %v", err)
+	}
+	return sha256.Sum256(encoded), nil
+}
@@ -0,0 +1,59 @@
+package keys
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestSignedManifest(t *testing.T) {
+	privKey, _ := hex.DecodeString("0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d")
+	for _, network := range Networks {
+		manifest, err := SignedManifest(privKey, network)
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+		}
+		for _, field := range []string{"0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d", "02d0de0aaeaefad02b8bdc8a01a1b8b11c696bd3d66a2c5f10780d95b7df42645c", network.String()} {
+			if !strings.Contains(manifest, field) {
+				t.Errorf("manifest %v should contain %v", manifest, field)
+			}
+		}
+		ok, err := VerifyManifest(manifest)
+		if err != nil || !ok {
+			t.Errorf("manifest on %v should verify, got %v %v", network, ok, err)
+		}
+	}
+	manifest, _ := SignedManifest(privKey, MainNet)
+	if !strings.Contains(manifest, `"address": "1LoVGDgRs9hTfTNJNuXKSpywcbdvwRXpmK"`) || !strings.Contains(manifest, `"wif": "KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617"`) {
+		t.Errorf("unexpected manifest %v", manifest)
+	}
+}
+
+func TestVerifyManifestTampered(t *testing.T) {
+	privKey, _ := hex.DecodeString("0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d")
+	other, _ := hex.DecodeString("e9873d79c6d87dc0fb6a5778633389f4453213303da61f20bd67fc233aa33262")
+	manifest, _ := SignedManifest(privKey, MainNet)
+	otherManifest, _ := SignedManifest(other, MainNet)
+	tampered := []string{
+		strings.Replace(manifest, "1LoVGDgRs9hTfTNJNuXKSpywcbdvwRXpmK", "1LoVGDgRs9hTfTNJNuXKSpywcbdvwRXpmL", 1),
+		strings.Replace(manifest, `"network": "mainnet"`, `"network": "testnet"`, 1),
+		strings.Replace(manifest, `"signature": "3`, `"signature": "4`, 1),
+		// signature of another manifest
+		manifest[:strings.Index(manifest, `"signature"`)] + otherManifest[strings.Index(otherManifest, `"signature"`):],
+	}
+	for _, m := range tampered {
+		ok, _ := VerifyManifest(m)
+		if ok {
+			t.Errorf("tampered manifest %v should not verify", m)
+		}
+	}
+	if _, err := VerifyManifest("{"); err == nil {
+		t.Errorf("invalid JSON should return an error")
+	}
+	if _, err := SignedManifest(make([]byte, 32), MainNet); err == nil {
+		t.Errorf("zero key should return an error")
+	}
+	if _, err := SignedManifest(privKey, Network(5)); err == nil {
+		t.Errorf("unknown network should return an error")
+	}
+}
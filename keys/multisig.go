@@ -0,0 +1,72 @@
+package keys
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcutil/base58"
+)
+
+// opCheckMultisig ends a bare multisig script, opOne is OP_1 (OP_2 to OP_16 follow it)
+const (
+	opCheckMultisig = 0xae
+	opOne           = 0x51
+)
+
+// maxMultisigKeys is the most keys of a standard P2SH multisig, maxRedeemScriptLength the biggest redeem script P2SH accepts
+const (
+	maxMultisigKeys       = 15
+	maxRedeemScriptLength = 520
+)
+
+// KeysFormMultisig tells if a required-of-len(pubKeys) P2SH multisig of the public keys has the target address, returning its redeem script
+// OP_required <pubKeys> OP_n OP_CHECKMULTISIG. The keys are tried in the given order and then sorted (BIP67), since wallets that sort them
+// make the order of the backup irrelevant; when neither matches the redeem script of the given order is returned
+func KeysFormMultisig(pubKeys [][]byte, required int, targetAddress string, network Network) (bool, []byte, error) {
+	if len(pubKeys) < 1 || len(pubKeys) > maxMultisigKeys {
+		return false, nil, fmt.Errorf("got %d public keys, must be between 1 and %d", len(pubKeys), maxMultisigKeys)
+	}
+	if required < 1 || required > len(pubKeys) {
+		return false, nil, fmt.Errorf("required signatures %d must be between 1 and %d", required, len(pubKeys))
+	}
+	for i, pubKey := range pubKeys {
+		if _, err := btcec.ParsePubKey(pubKey, btcec.S256()); err != nil {
+			return false, nil, fmt.Errorf("cannot parse public key at index %d due to %v", i, err)
+		}
+	}
+	targetHash, version, err := base58.CheckDecode(targetAddress)
+	if err != nil {
+		return false, nil, fmt.Errorf("cannot decode address %v due to %v", targetAddress, err)
+	}
+	if version != network.ScriptHashVersion() || len(targetHash) != 20 {
+		return false, nil, fmt.Errorf("address %v is not a P2SH address on %v", targetAddress, network)
+	}
+	sorted := append([][]byte{}, pubKeys...)
+	sort.Slice(sorted, func(i, j int) bool { return bytes.Compare(sorted[i], sorted[j]) < 0 })
+	var given []byte
+	for _, keys := range [][][]byte{pubKeys, sorted} {
+		redeemScript := multisigRedeemScript(keys, required)
+		if len(redeemScript) > maxRedeemScriptLength {
+			return false, nil, fmt.Errorf("redeem script is %d bytes long, P2SH allows at most %d", len(redeemScript), maxRedeemScriptLength)
+		}
+		if bytes.Equal(Hashed(redeemScript), targetHash) {
+			return true, redeemScript, nil
+		}
+		if given == nil {
+			given = redeemScript
+		}
+	}
+	return false, given, nil
+}
+
+// multisigRedeemScript returns OP_required <pubKeys> OP_n OP_CHECKMULTISIG, pushing each key with its length
+func multisigRedeemScript(pubKeys [][]byte, required int) []byte {
+	script := []byte{byte(opOne - 1 + required)}
+	for _, pubKey := range pubKeys {
+		script = append(script, byte(len(pubKey)))
+		script = append(script, pubKey...)
+	}
+	return append(script, byte(opOne-1+len(pubKeys)), opCheckMultisig)
+}
@@ -0,0 +1,74 @@
+package keys
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestKeysFormMultisig(t *testing.T) {
+	// https://github.com/bitcoin/bips/blob/master/bip-0067.mediawiki#test-vectors, vector 1
+	first, _ := hex.DecodeString("02ff12471208c14bd580709cb2358d98975247d8765f92bc25eab3b2763ed605f8")
+	second, _ := hex.DecodeString("02fe6f0a5a297eb38c391581c4413e084773ea23954d93f7753db7dc0adc188b2f")
+	expectedScript := "522102fe6f0a5a297eb38c391581c4413e084773ea23954d93f7753db7dc0adc188b2f2102ff12471208c14bd580709cb2358d98975247d8765f92bc25eab3b2763ed605f852ae"
+	// Address, network
+	expected := [][]string{
+		[]string{"39bgKC7RFbpoCRbtD5KEdkYKtNyhpsNa3Z", "mainnet"},
+		[]string{"2N19tNw3Ss4L9QDERtCw7FhXb6jBsYmeXNu", "testnet"},
+	}
+	for _, v := range expected {
+		network := MainNet
+		if v[1] == "testnet" {
+			network = TestNet
+		}
+		// the keys are given unsorted, the address is of the sorted ones
+		match, redeemScript, err := KeysFormMultisig([][]byte{first, second}, 2, v[0], network)
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+		}
+		if !match || hex.EncodeToString(redeemScript) != expectedScript {
+			t.Errorf("keys should form %v with script %v, got %v %x", v[0], expectedScript, match, redeemScript)
+		}
+	}
+	// uncompressed public keys of the private keys 1, 2 and 3, in the given order
+	uncompressed := [][]byte{}
+	for _, k := range []string{"01", "02", "03"} {
+		privKey, _ := PadHexKey(k)
+		uncompressed = append(uncompressed, Public(privKey, false))
+	}
+	match, _, err := KeysFormMultisig(uncompressed, 2, "34wnq8hB5qhmohkwdjcKEpTCXSN3VL8rQn", MainNet)
+	if err != nil || !match {
+		t.Errorf("2 of 3 uncompressed keys should match, got %v %v", match, err)
+	}
+	// wrong threshold
+	match, redeemScript, err := KeysFormMultisig([][]byte{first, second}, 1, "39bgKC7RFbpoCRbtD5KEdkYKtNyhpsNa3Z", MainNet)
+	if err != nil || match {
+		t.Errorf("1 of 2 should not match, got %v %v", match, err)
+	}
+	if len(redeemScript) == 0 || redeemScript[0] != 0x51 {
+		t.Errorf("redeem script of the given order should be returned, got %x", redeemScript)
+	}
+}
+
+func TestKeysFormMultisigErrors(t *testing.T) {
+	first, _ := hex.DecodeString("02ff12471208c14bd580709cb2358d98975247d8765f92bc25eab3b2763ed605f8")
+	second, _ := hex.DecodeString("02fe6f0a5a297eb38c391581c4413e084773ea23954d93f7753db7dc0adc188b2f")
+	pair := [][]byte{first, second}
+	if _, _, err := KeysFormMultisig(pair, 3, "39bgKC7RFbpoCRbtD5KEdkYKtNyhpsNa3Z", MainNet); err == nil {
+		t.Errorf("3 of 2 should return an error")
+	}
+	if _, _, err := KeysFormMultisig(pair, 0, "39bgKC7RFbpoCRbtD5KEdkYKtNyhpsNa3Z", MainNet); err == nil {
+		t.Errorf("0 of 2 should return an error")
+	}
+	if _, _, err := KeysFormMultisig(nil, 1, "39bgKC7RFbpoCRbtD5KEdkYKtNyhpsNa3Z", MainNet); err == nil {
+		t.Errorf("no keys should return an error")
+	}
+	if _, _, err := KeysFormMultisig([][]byte{first, first[:32]}, 1, "39bgKC7RFbpoCRbtD5KEdkYKtNyhpsNa3Z", MainNet); err == nil {
+		t.Errorf("invalid key should return an error")
+	}
+	if _, _, err := KeysFormMultisig(pair, 2, "39bgKC7RFbpoCRbtD5KEdkYKtNyhpsNa3Z", TestNet); err == nil {
+		t.Errorf("mainnet address on testnet should return an error")
+	}
+	if _, _, err := KeysFormMultisig(pair, 2, "1LoVGDgRs9hTfTNJNuXKSpywcbdvwRXpmK", MainNet); err == nil {
+		t.Errorf("P2PKH address should return an error")
+	}
+}
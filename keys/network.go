@@ -0,0 +1,65 @@
+package keys
+
+// Network identifies the bitcoin network a key belongs to
+type Network int
+
+const (
+	// MainNet is the main bitcoin network
+	MainNet Network = iota
+	// TestNet is the bitcoin test network (signet uses the same version bytes and human readable part)
+	TestNet
+	// RegTest is the local regression test network, sharing the version bytes of TestNet but not its human readable part
+	RegTest
+)
+
+// Networks lists all the supported networks
+var Networks = []Network{MainNet, TestNet, RegTest}
+
+// String returns the name of the network
+func (n Network) String() string {
+	switch n {
+	case MainNet:
+		return "mainnet"
+	case TestNet:
+		return "testnet"
+	case RegTest:
+		return "regtest"
+	default:
+		return "unknown"
+	}
+}
+
+// WIFVersion returns the version byte prepended to WIF private keys on the network
+func (n Network) WIFVersion() byte {
+	if n == TestNet || n == RegTest {
+		return 0xEF
+	}
+	return 0x80
+}
+
+// PubKeyHashVersion returns the version byte prepended to P2PKH addresses on the network
+func (n Network) PubKeyHashVersion() byte {
+	if n == TestNet || n == RegTest {
+		return 0x6F
+	}
+	return 0x00
+}
+
+// ScriptHashVersion returns the version byte prepended to P2SH addresses on the network
+func (n Network) ScriptHashVersion() byte {
+	if n == TestNet || n == RegTest {
+		return 0xC4
+	}
+	return 0x05
+}
+
+// Bech32HRP returns the human readable part of SegWit addresses on the network
+func (n Network) Bech32HRP() string {
+	switch n {
+	case TestNet:
+		return "tb"
+	case RegTest:
+		return "bcrt"
+	}
+	return "bc"
+}
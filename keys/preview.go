@@ -0,0 +1,50 @@
+package keys
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcutil/base58"
+)
+
+// PreviewData describes the key of a sequence without its secret: the compressed public key, its mainnet P2PKH address
+// and the entropy report of the sequence
+type PreviewData struct {
+	SourceType string
+	PublicKey  []byte
+	Address    string
+	Entropy    EntropyReportData
+}
+
+// PreviewKey validates a dice, coinflip or hex sequence and returns the public side of its key, so that the address can be
+// checked before the private key is ever shown. The private key is derived only to compute the public key and is wiped afterwards
+func PreviewKey(sequence string, sourceType string) (PreviewData, error) {
+	var privKey []byte
+	var err error
+	switch sourceType {
+	case SourceDice:
+		privKey, err = FromDiceSequence(sequence)
+	case SourceCoinflip:
+		privKey, err = FromCoinflipSequence(sequence)
+	case SourceHex:
+		privKey, err = FromHexSequence(sequence)
+	default:
+		return PreviewData{}, fmt.Errorf("unknown source type %v, must be one of %v, %v, %v", sourceType, SourceDice, SourceCoinflip, SourceHex)
+	}
+	if err != nil {
+		return PreviewData{}, err
+	}
+	pubKey := Public(privKey, true)
+	for i := range privKey {
+		privKey[i] = 0
+	}
+	report, err := EntropyReport(sequence, sourceType)
+	if err != nil {
+		return PreviewData{}, err
+	}
+	return PreviewData{
+		SourceType: sourceType,
+		PublicKey:  pubKey,
+		Address:    base58.CheckEncode(Hashed(pubKey), MainNet.PubKeyHashVersion()),
+		Entropy:    report,
+	}, nil
+}
@@ -0,0 +1,42 @@
+package keys
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestPreviewKey(t *testing.T) {
+	preview, err := PreviewKey("0C28FCA386C7A227600B2FE50B7CAE11EC86D3BF1FBE471BE89827E19D72AA1D", SourceHex)
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	expectedPub := "02d0de0aaeaefad02b8bdc8a01a1b8b11c696bd3d66a2c5f10780d95b7df42645c"
+	if hex.EncodeToString(preview.PublicKey) != expectedPub {
+		t.Errorf("public key should be %v but is %x", expectedPub, preview.PublicKey)
+	}
+	if preview.Address != "1LoVGDgRs9hTfTNJNuXKSpywcbdvwRXpmK" {
+		t.Errorf("address should be 1LoVGDgRs9hTfTNJNuXKSpywcbdvwRXpmK but is %v", preview.Address)
+	}
+	if preview.SourceType != SourceHex || preview.Entropy.Length != HexSeqRequiredLength || !preview.Entropy.Pass {
+		t.Errorf("unexpected preview %+v", preview)
+	}
+	dice := strings.Repeat("1", DiceSeqRequiredLength-1) + "2"
+	preview, err = PreviewKey(dice, SourceDice)
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	if preview.Address != "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH" || preview.Entropy.Pass {
+		t.Errorf("unexpected preview %+v", preview)
+	}
+	wrong := [][]string{
+		[]string{"0C28FCA3", SourceHex},
+		[]string{strings.Repeat("0", CoinflipSeqRequiredLength), SourceCoinflip},
+		[]string{dice, "base64"},
+	}
+	for _, w := range wrong {
+		if _, err := PreviewKey(w[0], w[1]); err == nil {
+			t.Errorf("%v sequence %v should return an error", w[1], w[0])
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package keys
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec"
+)
+
+// ParsePublicKey validates a serialized public key (33 bytes compressed or 65 bytes uncompressed) and returns it as given.
+// In tolerant mode a 34 bytes compressed key carrying a stray zero byte after the prefix, as written by some tools,
+// is normalized to the standard 33 bytes form and a warning describing the fix is returned
+func ParsePublicKey(pubKey []byte, tolerant bool) (parsed []byte, warning string, err error) {
+	if tolerant && len(pubKey) == 34 && (pubKey[0] == 0x02 || pubKey[0] == 0x03) && pubKey[1] == 0x00 {
+		pubKey = append([]byte{pubKey[0]}, pubKey[2:]...)
+		warning = "removed a stray zero byte padding the X coordinate of the compressed public key"
+	}
+	switch len(pubKey) {
+	case 33, 65:
+	default:
+		return nil, "", fmt.Errorf("public key is %d bytes long, must be 33 (compressed) or 65 (uncompressed)", len(pubKey))
+	}
+	if _, err := btcec.ParsePubKey(pubKey, btcec.S256()); err != nil {
+		return nil, "", fmt.Errorf("cannot parse public key due to %v", err)
+	}
+	return pubKey, warning, nil
+}
+
+// DecompressPubKey returns the 65 bytes uncompressed form of a serialized public key (compressed keys have their Y recovered
+// from the curve equation, uncompressed ones are returned after validation)
+func DecompressPubKey(pubKey []byte) ([]byte, error) {
+	pub, err := parseStandardPubKey(pubKey)
+	if err != nil {
+		return nil, err
+	}
+	return toUncompressedBytes(*pub.ToECDSA()), nil
+}
+
+// CompressPubKey returns the 33 bytes compressed form of a serialized public key
+func CompressPubKey(pubKey []byte) ([]byte, error) {
+	pub, err := parseStandardPubKey(pubKey)
+	if err != nil {
+		return nil, err
+	}
+	return toCompressedBytes(*pub.ToECDSA()), nil
+}
+
+// parseStandardPubKey parses a 33 bytes key with prefix 0x02 or 0x03 or a 65 bytes key with prefix 0x04, rejecting hybrid keys (0x06, 0x07)
+func parseStandardPubKey(pubKey []byte) (*btcec.PublicKey, error) {
+	switch {
+	case len(pubKey) == 33 && (pubKey[0] == 0x02 || pubKey[0] == 0x03):
+	case len(pubKey) == 65 && pubKey[0] == 0x04:
+	case len(pubKey) == 0:
+		return nil, fmt.Errorf("public key is empty")
+	default:
+		return nil, fmt.Errorf("public key of %d bytes with prefix 0x%02x is neither compressed (33 bytes, 0x02 or 0x03) nor uncompressed (65 bytes, 0x04)", len(pubKey), pubKey[0])
+	}
+	pub, err := btcec.ParsePubKey(pubKey, btcec.S256())
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse public key due to %v", err)
+	}
+	return pub, nil
+}
@@ -0,0 +1,77 @@
+package keys
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestParsePublicKey(t *testing.T) {
+	compressed := "0250863ad64a87ae8a2fe83c1af1a8403cb53f53e486d8511dad8a04887e5b2352"
+	uncompressed := "0450863ad64a87ae8a2fe83c1af1a8403cb53f53e486d8511dad8a04887e5b23522cd470243453a299fa9e77237716103abc11a1df38855ed6f2ee187e9c582ba6"
+	padded := "0200" + compressed[2:]
+	// Input, tolerant, expected output (empty if an error is expected), warning expected
+	expected := [][]string{
+		[]string{compressed, "false", compressed, "false"},
+		[]string{compressed, "true", compressed, "false"},
+		[]string{uncompressed, "false", uncompressed, "false"},
+		[]string{padded, "true", compressed, "true"},
+		[]string{padded, "false", "", "false"},
+		[]string{"0400" + compressed[2:], "true", "", "false"},
+		[]string{"0201" + compressed[2:], "true", "", "false"},
+		[]string{compressed[:64], "true", "", "false"},
+		[]string{"05" + compressed[2:], "false", "", "false"},
+	}
+	for _, v := range expected {
+		pubKey, _ := hex.DecodeString(v[0])
+		parsed, warning, err := ParsePublicKey(pubKey, v[1] == "true")
+		if v[2] == "" {
+			if err == nil {
+				t.Errorf("public key %v (tolerant %v) should not be accepted", v[0], v[1])
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("cannot parse public key %v (tolerant %v) due to %v", v[0], v[1], err)
+			continue
+		}
+		if hex.EncodeToString(parsed) != v[2] {
+			t.Errorf("public key %v should be parsed as %v but is %x", v[0], v[2], parsed)
+		}
+		if (warning != "") != (v[3] == "true") {
+			t.Errorf("public key %v returned unexpected warning %q", v[0], warning)
+		}
+	}
+}
+
+func TestCompressPubKey(t *testing.T) {
+	// Compressed, uncompressed
+	expected := [][]string{
+		[]string{"0250863ad64a87ae8a2fe83c1af1a8403cb53f53e486d8511dad8a04887e5b2352", "0450863ad64a87ae8a2fe83c1af1a8403cb53f53e486d8511dad8a04887e5b23522cd470243453a299fa9e77237716103abc11a1df38855ed6f2ee187e9c582ba6"},
+		// public key of n - 1, odd Y
+		[]string{"0379be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", "0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798b7c52588d95c3b9aa25b0403f1eef75702e84bb7597aabe663b82f6f04ef2777"},
+	}
+	for _, v := range expected {
+		compressed, _ := hex.DecodeString(v[0])
+		uncompressed, _ := hex.DecodeString(v[1])
+		for _, input := range [][]byte{compressed, uncompressed} {
+			got, err := DecompressPubKey(input)
+			if err != nil || hex.EncodeToString(got) != v[1] {
+				t.Errorf("uncompressed key of %x should be %v but is %x (%v)", input, v[1], got, err)
+			}
+			got, err = CompressPubKey(input)
+			if err != nil || hex.EncodeToString(got) != v[0] {
+				t.Errorf("compressed key of %x should be %v but is %x (%v)", input, v[0], got, err)
+			}
+		}
+	}
+	hybrid, _ := hex.DecodeString("06" + expected[0][1][2:])
+	wrong := [][]byte{nil, hybrid, hybrid[:33], make([]byte, 33)}
+	for _, w := range wrong {
+		if _, err := DecompressPubKey(w); err == nil {
+			t.Errorf("public key %x should return an error", w)
+		}
+		if _, err := CompressPubKey(w); err == nil {
+			t.Errorf("public key %x should return an error", w)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package keys
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+)
+
+// maxRandomAttempts bounds the candidates drawn by NewRandomKeyWithStats: each one is rejected with probability about 2^-128,
+// so reaching the limit means the random source is broken
+const maxRandomAttempts = 64
+
+// randomSource is the entropy source of random keys, replaceable in tests
+var randomSource io.Reader = rand.Reader
+
+// Random returns a private key read from crypto/rand, drawing again whenever the 32 bytes are zero or not below the curve order
+func Random() (key []byte, err error) {
+	key, _, err = NewRandomKeyWithStats()
+	return key, err
+}
+
+// GenerateBatch returns n independent random private keys, generated by Random over OptimalWorkerCount() goroutines
+func GenerateBatch(n int) (keys [][]byte, err error) {
+	if n < 1 {
+		return nil, fmt.Errorf("batch size must be at least 1, got %d", n)
+	}
+	keys = make([][]byte, n)
+	errs := make([]error, n)
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < OptimalWorkerCount(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				keys[i], errs[i] = Random()
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("cannot generate key %d due to %v", i, err)
+		}
+	}
+	return keys, nil
+}
+
+// NewRandomKeyWithStats returns a private key read from crypto/rand, with the number of 32 bytes candidates rejected before it
+// because out of range (zero or not below the curve order). For secp256k1 this is almost impossible, so anything other than 0
+// is a strong hint that the random number generator is broken
+func NewRandomKeyWithStats() (key []byte, rejected int, err error) {
+	candidate := make([]byte, 32)
+	for rejected = 0; rejected < maxRandomAttempts; rejected++ {
+		if _, err := io.ReadFull(randomSource, candidate); err != nil {
+			return nil, rejected, fmt.Errorf("cannot read random bytes due to %v", err)
+		}
+		if isValidKey(new(big.Int).SetBytes(candidate)) {
+			return candidate, rejected, nil
+		}
+	}
+	return nil, rejected, errors.New("too many out of range random candidates, the random source looks broken")
+}
@@ -0,0 +1,99 @@
+package keys
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"math/big"
+	"testing"
+)
+
+func TestNewRandomKeyWithStats(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		key, rejected, err := NewRandomKeyWithStats()
+		if err != nil {
+			t.Fatalf("failed due to %v\n", err)
+		}
+		if len(key) != 32 || !isValidKey(new(big.Int).SetBytes(key)) {
+			t.Errorf("key %x is not valid", key)
+		}
+		if rejected != 0 {
+			t.Errorf("%d candidates rejected, the random source looks broken", rejected)
+		}
+	}
+}
+
+func TestNewRandomKeyWithStatsRejection(t *testing.T) {
+	defer func(source io.Reader) { randomSource = source }(randomSource)
+	zero := make([]byte, 32)
+	tooBig, _ := hex.DecodeString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141")
+	valid, _ := hex.DecodeString("0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d")
+	randomSource = bytes.NewReader(append(append(zero, tooBig...), valid...))
+	key, rejected, err := NewRandomKeyWithStats()
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	if rejected != 2 {
+		t.Errorf("2 candidates should be rejected but were %d", rejected)
+	}
+	if !bytes.Equal(key, valid) {
+		t.Errorf("key should be %x but is %x", valid, key)
+	}
+	randomSource = bytes.NewReader(bytes.Repeat(zero, maxRandomAttempts))
+	if _, _, err := NewRandomKeyWithStats(); err == nil {
+		t.Errorf("always zero source should return an error")
+	}
+	randomSource = bytes.NewReader(zero[:10])
+	if _, _, err := NewRandomKeyWithStats(); err == nil {
+		t.Errorf("short read should return an error")
+	}
+}
+
+func TestRandom(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		key, err := Random()
+		if err != nil {
+			t.Fatalf("failed due to %v\n", err)
+		}
+		if len(key) != 32 || !isValidKey(new(big.Int).SetBytes(key)) {
+			t.Errorf("key %x is not valid", key)
+		}
+		if seen[hex.EncodeToString(key)] {
+			t.Errorf("key %x generated twice", key)
+		}
+		seen[hex.EncodeToString(key)] = true
+	}
+	defer func(source io.Reader) { randomSource = source }(randomSource)
+	tooBig, _ := hex.DecodeString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141")
+	valid, _ := hex.DecodeString("0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d")
+	randomSource = bytes.NewReader(append(tooBig, valid...))
+	if key, err := Random(); err != nil || !bytes.Equal(key, valid) {
+		t.Errorf("out of range candidate should be retried, got %x %v", key, err)
+	}
+}
+
+func TestGenerateBatch(t *testing.T) {
+	batch, err := GenerateBatch(1000)
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	if len(batch) != 1000 {
+		t.Fatalf("batch should have 1000 keys, has %d", len(batch))
+	}
+	seen := make(map[string]bool, len(batch))
+	for _, key := range batch {
+		if len(key) != 32 || !isValidKey(new(big.Int).SetBytes(key)) {
+			t.Errorf("key %x is not valid", key)
+		}
+		if seen[string(key)] {
+			t.Errorf("key %x generated twice", key)
+		}
+		seen[string(key)] = true
+	}
+	for _, n := range []int{0, -1} {
+		if _, err := GenerateBatch(n); err == nil {
+			t.Errorf("batch size %d should return an error", n)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package keys
+
+import (
+	"fmt"
+	"math"
+)
+
+// randomnessSignificance is the significance level of the randomness tests: a random input fails each test with this probability
+const randomnessSignificance = 0.01
+
+// minRandomnessBytes is the minimum input for the longest run test (128 bits)
+const minRandomnessBytes = 16
+
+// longest run test parameters (NIST SP 800-22, 2.4): block length, class bounds of the longest run and class probabilities
+var (
+	longestRunShort = longestRunParams{blockBits: 8, minClass: 1, probabilities: []float64{0.2148, 0.3672, 0.2305, 0.2266}}
+	longestRunLong  = longestRunParams{blockBits: 128, minClass: 4, probabilities: []float64{0.1174, 0.2430, 0.2493, 0.1752, 0.1027, 0.1124}}
+)
+
+type longestRunParams struct {
+	blockBits     int
+	minClass      int // longest runs up to minClass fall in the first class, each next class is one longer, the last one is open
+	probabilities []float64
+}
+
+// RandomnessTestResult is the outcome of a single statistical test
+type RandomnessTestResult struct {
+	PValue float64
+	Pass   bool
+}
+
+// RandomnessResult is the outcome of the tests run by RandomnessTest on a sequence of bits
+type RandomnessResult struct {
+	Bits       int
+	Monobit    RandomnessTestResult // proportion of ones and zeros
+	Runs       RandomnessTestResult // number of runs of identical bits
+	LongestRun RandomnessTestResult // longest run of ones within blocks
+	Pass       bool
+}
+
+// RandomnessTest runs the frequency (monobit), runs and longest run of ones tests of NIST SP 800-22 on data, at least 16 bytes read most significant bit first,
+// and reports the p-value of each test, passed when not below 0.01.
+// The tests only spot gross bias (a stuck bit, a biased coin, repeated patterns): passing them does not prove the data is random or secret,
+// a counter or a hash of a known value passes too, and with so few bits a good source still fails one test out of about 30 runs
+func RandomnessTest(data []byte) (RandomnessResult, error) {
+	if len(data) < minRandomnessBytes {
+		return RandomnessResult{}, fmt.Errorf("input is %d bytes long, must be at least %d", len(data), minRandomnessBytes)
+	}
+	bits := make([]byte, 0, len(data)*8)
+	for _, b := range data {
+		for i := 7; i >= 0; i-- {
+			bits = append(bits, (b>>uint(i))&1)
+		}
+	}
+	result := RandomnessResult{Bits: len(bits)}
+	result.Monobit = randomnessResult(monobitPValue(bits))
+	result.Runs = randomnessResult(runsPValue(bits))
+	params := longestRunShort
+	if len(bits) >= 6272 {
+		params = longestRunLong
+	}
+	result.LongestRun = randomnessResult(longestRunPValue(bits, params))
+	result.Pass = result.Monobit.Pass && result.Runs.Pass && result.LongestRun.Pass
+	return result, nil
+}
+
+func randomnessResult(pValue float64) RandomnessTestResult {
+	return RandomnessTestResult{PValue: pValue, Pass: pValue >= randomnessSignificance}
+}
+
+// monobitPValue tests that ones and zeros are about the same number
+func monobitPValue(bits []byte) float64 {
+	sum := 0
+	for _, bit := range bits {
+		sum += 2*int(bit) - 1
+	}
+	n := float64(len(bits))
+	return math.Erfc(math.Abs(float64(sum)) / math.Sqrt(2*n))
+}
+
+// runsPValue tests that the bits change value as often as expected, neither too slowly nor too fast
+func runsPValue(bits []byte) float64 {
+	n := float64(len(bits))
+	ones := 0
+	for _, bit := range bits {
+		ones += int(bit)
+	}
+	pi := float64(ones) / n
+	// the test is meaningless when the monobit frequency is already too far from one half
+	if math.Abs(pi-0.5) >= 2/math.Sqrt(n) {
+		return 0
+	}
+	runs := 1
+	for i := 1; i < len(bits); i++ {
+		if bits[i] != bits[i-1] {
+			runs++
+		}
+	}
+	return math.Erfc(math.Abs(float64(runs)-2*n*pi*(1-pi)) / (2 * math.Sqrt(2*n) * pi * (1 - pi)))
+}
+
+// longestRunPValue tests that the longest runs of ones within blocks follow the distribution expected for random bits
+func longestRunPValue(bits []byte, params longestRunParams) float64 {
+	blocks := len(bits) / params.blockBits
+	classes := len(params.probabilities)
+	counts := make([]int, classes)
+	for b := 0; b < blocks; b++ {
+		longest, run := 0, 0
+		for _, bit := range bits[b*params.blockBits : (b+1)*params.blockBits] {
+			if bit == 1 {
+				run++
+				if run > longest {
+					longest = run
+				}
+			} else {
+				run = 0
+			}
+		}
+		class := longest - params.minClass
+		if class < 0 {
+			class = 0
+		}
+		if class > classes-1 {
+			class = classes - 1
+		}
+		counts[class]++
+	}
+	chiSquare := 0.0
+	for i, p := range params.probabilities {
+		expected := float64(blocks) * p
+		chiSquare += (float64(counts[i]) - expected) * (float64(counts[i]) - expected) / expected
+	}
+	return upperIncompleteGamma(float64(classes-1)/2, chiSquare/2)
+}
+
+// upperIncompleteGamma returns the regularized upper incomplete gamma Q(a, x) for a positive integer or half integer,
+// through its closed forms: e^-x * sum x^k/k! for integer a, erfc(sqrt(x)) plus e^-x * sum x^(k+1/2)/Gamma(k+3/2) for half integer a
+func upperIncompleteGamma(a, x float64) float64 {
+	sum := 0.0
+	if a == math.Floor(a) {
+		term := 1.0
+		for k := 0; k < int(a); k++ {
+			sum += term
+			term *= x / float64(k+1)
+		}
+		return math.Exp(-x) * sum
+	}
+	term := math.Sqrt(x) / math.Gamma(1.5)
+	for k := 0; k < int(a); k++ {
+		sum += term
+		term *= x / (float64(k) + 1.5)
+	}
+	return math.Erfc(math.Sqrt(x)) + math.Exp(-x)*sum
+}
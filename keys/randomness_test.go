@@ -0,0 +1,59 @@
+package keys
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"testing"
+)
+
+func TestRandomnessTest(t *testing.T) {
+	// example sequence of NIST SP 800-22, 2.4.8, p-values recomputed from the longest run classes 4, 9, 3, 0
+	data, _ := hex.DecodeString("cc156c4ce0024d5113d680d7cce6d8b2")
+	result, err := RandomnessTest(data)
+	if err != nil {
+		t.Errorf("failed due to %v\n", err)
+	}
+	if result.Bits != 128 || !result.Pass {
+		t.Errorf("unexpected result %+v", result)
+	}
+	expected := []float64{0.215925, 0.620729, 0.138149}
+	got := []float64{result.Monobit.PValue, result.Runs.PValue, result.LongestRun.PValue}
+	for i := range expected {
+		if math.Abs(expected[i]-got[i]) > 1e-5 {
+			t.Errorf("expected p-value %v, got %v", expected[i], got[i])
+		}
+	}
+	// 8192 bits use the 128 bits blocks of the longest run test
+	var long []byte
+	for i := 0; i < 32; i++ {
+		hash := sha256.Sum256([]byte{byte(i)})
+		long = append(long, hash[:]...)
+	}
+	result, err = RandomnessTest(long)
+	if err != nil {
+		t.Errorf("failed due to %v\n", err)
+	}
+	expected = []float64{0.536102, 0.985744, 0.508313}
+	got = []float64{result.Monobit.PValue, result.Runs.PValue, result.LongestRun.PValue}
+	for i := range expected {
+		if math.Abs(expected[i]-got[i]) > 1e-5 {
+			t.Errorf("expected p-value %v, got %v", expected[i], got[i])
+		}
+	}
+}
+
+func TestRandomnessTestFailures(t *testing.T) {
+	stuck, _ := RandomnessTest(bytes.Repeat([]byte{0xff}, 32))
+	if stuck.Pass || stuck.Monobit.Pass || stuck.Runs.Pass || stuck.LongestRun.Pass {
+		t.Errorf("all ones should fail every test, got %+v", stuck)
+	}
+	alternating, _ := RandomnessTest(bytes.Repeat([]byte{0x55}, 32))
+	if alternating.Pass || !alternating.Monobit.Pass || alternating.Runs.Pass {
+		t.Errorf("alternating bits should pass monobit and fail runs, got %+v", alternating)
+	}
+	if _, err := RandomnessTest(make([]byte, 15)); err == nil {
+		t.Errorf("short input should return an error")
+	}
+}
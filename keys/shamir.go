@@ -0,0 +1,161 @@
+package keys
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/savardiego/cashline/segwit"
+)
+
+// shareLength is the length of a share made by SplitKey: the x coordinate (1-255) followed by the 32 bytes of the y values
+const shareLength = 33
+
+// SplitKey splits a private key in total Shamir shares of 33 bytes, any required of which give back the key with CombineAndVerify.
+// Each byte of the key is the constant term of its own random polynomial of degree required-1 over GF(256)
+// (AES polynomial x^8 + x^4 + x^3 + x + 1), a share is the byte x followed by the 32 values of the polynomials at x
+func SplitKey(privKey []byte, required, total int) ([][]byte, error) {
+	if len(privKey) != 32 || !isValidKey(new(big.Int).SetBytes(privKey)) {
+		return nil, errors.New("given key is not acceptable as private key")
+	}
+	if total < 1 || total > 255 {
+		return nil, fmt.Errorf("total shares %d must be between 1 and 255", total)
+	}
+	if required < 1 || required > total {
+		return nil, fmt.Errorf("required shares %d must be between 1 and %d", required, total)
+	}
+	// coefficients[i] holds the coefficients of degree 1 to required-1 of the polynomial of byte i
+	coefficients := make([]byte, 32*(required-1))
+	if _, err := io.ReadFull(rand.Reader, coefficients); err != nil {
+		return nil, fmt.Errorf("cannot read random coefficients due to %v", err)
+	}
+	defer wipe(coefficients)
+	shares := make([][]byte, total)
+	for s := 0; s < total; s++ {
+		x := byte(s + 1)
+		share := make([]byte, shareLength)
+		share[0] = x
+		for i := 0; i < 32; i++ {
+			// Horner, from the highest degree down to the constant term
+			y := byte(0)
+			for d := required - 2; d >= 0; d-- {
+				y = gfMul(y, x) ^ coefficients[i*(required-1)+d]
+			}
+			share[1+i] = gfMul(y, x) ^ privKey[i]
+		}
+		shares[s] = share
+	}
+	return shares, nil
+}
+
+// combineShares returns the secret interpolated at x = 0 from shares made by SplitKey. Shares fewer than the required ones,
+// or corrupted ones, still give 32 bytes but not the split key
+func combineShares(shares [][]byte) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, errors.New("no shares given")
+	}
+	seen := make(map[byte]bool)
+	for i, share := range shares {
+		if len(share) != shareLength {
+			return nil, fmt.Errorf("share at index %d is %d bytes long, must be %d", i, len(share), shareLength)
+		}
+		if share[0] == 0 {
+			return nil, fmt.Errorf("share at index %d has x coordinate 0", i)
+		}
+		if seen[share[0]] {
+			return nil, fmt.Errorf("share at index %d repeats x coordinate %d", i, share[0])
+		}
+		seen[share[0]] = true
+	}
+	secret := make([]byte, 32)
+	for j, share := range shares {
+		// Lagrange basis at 0: product of x_m / (x_m - x_j), subtraction is xor in GF(256)
+		basis := byte(1)
+		for m, other := range shares {
+			if m != j {
+				basis = gfMul(basis, gfMul(other[0], gfInv(other[0]^share[0])))
+			}
+		}
+		for i := 0; i < 32; i++ {
+			secret[i] ^= gfMul(basis, share[1+i])
+		}
+	}
+	return secret, nil
+}
+
+// CombineAndVerify combines shares made by SplitKey and returns the key only if one of its addresses on the network
+// (P2PKH of the compressed or uncompressed public key, P2WPKH or P2SH-P2WPKH) is the expected address: missing or corrupted
+// shares still combine to 32 bytes, the address tells them from the right key
+func CombineAndVerify(shares [][]byte, expectedAddress string, network Network) ([]byte, error) {
+	if network.String() == "unknown" {
+		return nil, fmt.Errorf("unknown network %d", network)
+	}
+	key, err := combineShares(shares)
+	if err != nil {
+		return nil, fmt.Errorf("cannot combine shares due to %v", err)
+	}
+	if !isValidKey(new(big.Int).SetBytes(key)) {
+		wipe(key)
+		return nil, errors.New("combined shares are not acceptable as private key, some shares are missing or corrupted")
+	}
+	addresses, err := candidateAddresses(key, network)
+	if err != nil {
+		wipe(key)
+		return nil, err
+	}
+	for _, address := range addresses {
+		if subtle.ConstantTimeCompare([]byte(address), []byte(expectedAddress)) == 1 {
+			return key, nil
+		}
+	}
+	wipe(key)
+	return nil, fmt.Errorf("combined key does not match address %v, some shares are missing or corrupted", expectedAddress)
+}
+
+// candidateAddresses returns the P2PKH (compressed and uncompressed), P2WPKH and P2SH-P2WPKH addresses of a private key
+func candidateAddresses(privKey []byte, network Network) ([]string, error) {
+	hash := Hashed(Public(privKey, true))
+	nativeSegwit, err := segwit.Encode(network.Bech32HRP(), 0, hash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode segwit address due to %v", err)
+	}
+	return []string{
+		base58.CheckEncode(hash, network.PubKeyHashVersion()),
+		base58.CheckEncode(Hashed(Public(privKey, false)), network.PubKeyHashVersion()),
+		nativeSegwit,
+		base58.CheckEncode(Hashed(append([]byte{0x00, 0x14}, hash...)), network.ScriptHashVersion()),
+	}, nil
+}
+
+// gfMul multiplies two elements of GF(256) modulo x^8 + x^4 + x^3 + x + 1 without branching on their values
+func gfMul(a, b byte) byte {
+	var product byte
+	for i := 0; i < 8; i++ {
+		product ^= a & -(b & 1)
+		carry := a >> 7
+		a = a<<1 ^ 0x1b&-carry
+		b >>= 1
+	}
+	return product
+}
+
+// gfInv returns the multiplicative inverse of a non zero element of GF(256), a^254
+func gfInv(a byte) byte {
+	result := byte(1)
+	for i := 0; i < 7; i++ {
+		a = gfMul(a, a)
+		result = gfMul(result, a)
+	}
+	return result
+}
+
+// wipe zeroes a buffer holding secret material
+func wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,114 @@
+package keys
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestGFMul(t *testing.T) {
+	// FIPS 197 section 4.2 and 4.2.1: {57}*{83} = {c1}, {53} and {ca} are inverses
+	if p := gfMul(0x57, 0x83); p != 0xc1 {
+		t.Errorf("0x57 * 0x83 should be 0xc1 but is 0x%02x", p)
+	}
+	if inv := gfInv(0x53); inv != 0xca {
+		t.Errorf("inverse of 0x53 should be 0xca but is 0x%02x", inv)
+	}
+	for a := 1; a < 256; a++ {
+		if p := gfMul(byte(a), gfInv(byte(a))); p != 1 {
+			t.Errorf("0x%02x times its inverse should be 1 but is 0x%02x", a, p)
+		}
+	}
+}
+
+func TestCombineShares(t *testing.T) {
+	// shares of a 2 of n split with coefficient 0x01 for every byte: y = x * 1 xor key
+	key, _ := hex.DecodeString("0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d")
+	shares := make([][]byte, 3)
+	for s := range shares {
+		x := byte(s + 1)
+		shares[s] = append([]byte{x}, key...)
+		for i := 1; i < shareLength; i++ {
+			shares[s][i] ^= x
+		}
+	}
+	for _, pair := range [][][]byte{{shares[0], shares[1]}, {shares[2], shares[0]}, {shares[1], shares[2]}} {
+		combined, err := combineShares(pair)
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+			continue
+		}
+		if hex.EncodeToString(combined) != hex.EncodeToString(key) {
+			t.Errorf("shares %x %x should give %x but give %x", pair[0][0], pair[1][0], key, combined)
+		}
+	}
+	if _, err := combineShares([][]byte{shares[0], shares[0]}); err == nil {
+		t.Errorf("repeated share should return an error")
+	}
+	if _, err := combineShares([][]byte{shares[0][:32]}); err == nil {
+		t.Errorf("short share should return an error")
+	}
+	if _, err := combineShares(nil); err == nil {
+		t.Errorf("no shares should return an error")
+	}
+}
+
+func TestCombineAndVerify(t *testing.T) {
+	key, _ := hex.DecodeString("0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d")
+	shares, err := SplitKey(key, 3, 5)
+	if err != nil {
+		t.Fatalf("cannot split key due to %v", err)
+	}
+	// P2PKH of the uncompressed and compressed public key, P2WPKH and P2SH-P2WPKH
+	addresses := []string{
+		"1GAehh7TsJAHuUAeKZcXf5CnwuGuGgyX2S",
+		"1LoVGDgRs9hTfTNJNuXKSpywcbdvwRXpmK",
+		"bc1qmy63mjadtw8nhzl69ukdepwzsyvv4yex5qlmkd",
+		"3D9iyFHi1Zs9KoyynUfrL82rGhJfYTfSG4",
+	}
+	for _, address := range addresses {
+		combined, err := CombineAndVerify([][]byte{shares[4], shares[0], shares[2]}, address, MainNet)
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+			continue
+		}
+		if hex.EncodeToString(combined) != hex.EncodeToString(key) {
+			t.Errorf("combined key should be %x but is %x", key, combined)
+		}
+	}
+	if _, err := CombineAndVerify(shares[:2], "1GAehh7TsJAHuUAeKZcXf5CnwuGuGgyX2S", MainNet); err == nil {
+		t.Errorf("2 of 3 required shares should not give the key")
+	}
+	corrupted := append([]byte{}, shares[1]...)
+	corrupted[10] ^= 0x01
+	_, err = CombineAndVerify([][]byte{shares[0], corrupted, shares[2]}, "1GAehh7TsJAHuUAeKZcXf5CnwuGuGgyX2S", MainNet)
+	if err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("corrupted share should not match the address, got %v", err)
+	}
+	if _, err := CombineAndVerify(shares[:3], "1GAehh7TsJAHuUAeKZcXf5CnwuGuGgyX2S", TestNet); err == nil {
+		t.Errorf("mainnet address should not match on testnet")
+	}
+}
+
+func TestSplitKey(t *testing.T) {
+	key, _ := hex.DecodeString("0000000000000000000000000000000000000000000000000000000000000001")
+	shares, err := SplitKey(key, 1, 2)
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	// with a single required share every share is the key itself
+	for _, share := range shares {
+		if len(share) != shareLength || hex.EncodeToString(share[1:]) != hex.EncodeToString(key) {
+			t.Errorf("1 of 2 share should hold the key, got %x", share)
+		}
+	}
+	wrong := [][]int{{0, 3}, {4, 3}, {1, 0}, {2, 256}}
+	for _, w := range wrong {
+		if _, err := SplitKey(key, w[0], w[1]); err == nil {
+			t.Errorf("%d of %d split should return an error", w[0], w[1])
+		}
+	}
+	if _, err := SplitKey(make([]byte, 32), 2, 3); err == nil {
+		t.Errorf("zero key should return an error")
+	}
+}
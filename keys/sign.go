@@ -0,0 +1,94 @@
+package keys
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec"
+)
+
+// Sign returns the DER encoded ECDSA signature of a message, signing the double sha256 of it as Bitcoin does
+func Sign(privKey []byte, message []byte) (signature []byte, err error) {
+	return SignHash(privKey, doubleSha256(message))
+}
+
+// SignHash returns the DER encoded ECDSA signature (deterministic RFC6979 nonce, low S) of a 32 bytes digest computed by the caller,
+// which is signed as is, without hashing it again
+func SignHash(privKey []byte, hash [32]byte) ([]byte, error) {
+	if len(privKey) != 32 || !isValidKey(new(big.Int).SetBytes(privKey)) {
+		return nil, errors.New("given key is not acceptable as private key")
+	}
+	priv, _ := btcec.PrivKeyFromBytes(btcec.S256(), privKey)
+	signature, err := priv.Sign(hash[:])
+	if err != nil {
+		return nil, fmt.Errorf("cannot sign hash due to %v", err)
+	}
+	return signature.Serialize(), nil
+}
+
+// VerifyHash checks a DER encoded ECDSA signature of a 32 bytes digest against a serialized public key
+func VerifyHash(pubKey []byte, hash [32]byte, sig []byte) bool {
+	pub, err := btcec.ParsePubKey(pubKey, btcec.S256())
+	if err != nil {
+		return false
+	}
+	signature, err := btcec.ParseDERSignature(sig, btcec.S256())
+	if err != nil {
+		return false
+	}
+	return signature.Verify(hash[:], pub)
+}
+
+// Verify tells if a DER encoded signature made by Sign matches the double sha256 of a message and a serialized public key
+// (compressed or uncompressed). Malformed public keys or signatures return an error, a non matching signature returns false
+func Verify(pubKey []byte, message []byte, signature []byte) (bool, error) {
+	pub, err := btcec.ParsePubKey(pubKey, btcec.S256())
+	if err != nil {
+		return false, fmt.Errorf("cannot parse public key due to %v", err)
+	}
+	sig, err := btcec.ParseDERSignature(signature, btcec.S256())
+	if err != nil {
+		return false, fmt.Errorf("cannot parse signature due to %v", err)
+	}
+	hash := doubleSha256(message)
+	return sig.Verify(hash[:], pub), nil
+}
+
+// SignCompact returns the 65 bytes compact signature (recovery byte, R, S) of the double sha256 of a message, from which RecoverPubKey
+// gets the public key back. The recovery byte also tells if the compressed or the uncompressed public key has to be recovered
+func SignCompact(privKey []byte, message []byte, compressed bool) ([]byte, error) {
+	if len(privKey) != 32 || !isValidKey(new(big.Int).SetBytes(privKey)) {
+		return nil, errors.New("given key is not acceptable as private key")
+	}
+	priv, _ := btcec.PrivKeyFromBytes(btcec.S256(), privKey)
+	hash := doubleSha256(message)
+	signature, err := btcec.SignCompact(btcec.S256(), priv, hash[:], compressed)
+	if err != nil {
+		return nil, fmt.Errorf("cannot sign message due to %v", err)
+	}
+	return signature, nil
+}
+
+// RecoverPubKey returns the public key that made a compact signature of the double sha256 of a message,
+// serialized compressed or uncompressed as the recovery byte of the signature says
+func RecoverPubKey(signature []byte, message []byte) (pubKey []byte, compressed bool, err error) {
+	if len(signature) != 65 {
+		return nil, false, fmt.Errorf("compact signature is %d bytes long, must be 65", len(signature))
+	}
+	hash := doubleSha256(message)
+	pub, compressed, err := btcec.RecoverCompact(btcec.S256(), signature, hash[:])
+	if err != nil {
+		return nil, false, fmt.Errorf("cannot recover public key due to %v", err)
+	}
+	if compressed {
+		return pub.SerializeCompressed(), true, nil
+	}
+	return pub.SerializeUncompressed(), false, nil
+}
+
+func doubleSha256(data []byte) [32]byte {
+	first := sha256.Sum256(data)
+	return sha256.Sum256(first[:])
+}
@@ -0,0 +1,123 @@
+package keys
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestSignHash(t *testing.T) {
+	// RFC6979 deterministic signature of sha256("Satoshi Nakamoto") with private key 1
+	privKey, _ := hex.DecodeString("0000000000000000000000000000000000000000000000000000000000000001")
+	hash := sha256.Sum256([]byte("Satoshi Nakamoto"))
+	expected := "3045022100934b1ea10a4b3c1757e2b0c017d0b6143ce3c9a7e6a4a49860d7a6ab210ee3d802202442ce9d2b916064108014783e923ec36b49743e2ffa1c4496f01a512aafd9e5"
+	sig, err := SignHash(privKey, hash)
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	if hex.EncodeToString(sig) != expected {
+		t.Errorf("signature should be %v but is %x", expected, sig)
+	}
+	for _, compressed := range []bool{true, false} {
+		pubKey := Public(privKey, compressed)
+		if !VerifyHash(pubKey, hash, sig) {
+			t.Errorf("signature should be valid for public key %x", pubKey)
+		}
+	}
+	otherHash := sha256.Sum256([]byte("Satoshi Nakamoto!"))
+	if VerifyHash(Public(privKey, true), otherHash, sig) {
+		t.Errorf("signature should not be valid for another hash")
+	}
+	otherKey, _ := hex.DecodeString("0C28FCA386C7A227600B2FE50B7CAE11EC86D3BF1FBE471BE89827E19D72AA1D")
+	if VerifyHash(Public(otherKey, true), hash, sig) {
+		t.Errorf("signature should not be valid for another key")
+	}
+	if VerifyHash(Public(privKey, true), hash, sig[:len(sig)-1]) {
+		t.Errorf("truncated signature should not be valid")
+	}
+	if VerifyHash([]byte{0x02}, hash, sig) {
+		t.Errorf("malformed public key should not be valid")
+	}
+	if _, err := SignHash(make([]byte, 32), hash); err == nil {
+		t.Errorf("zero key should return an error")
+	}
+	if _, err := SignHash(privKey[1:], hash); err == nil {
+		t.Errorf("short key should return an error")
+	}
+}
+
+func TestSign(t *testing.T) {
+	privKey, _ := hex.DecodeString("0C28FCA386C7A227600B2FE50B7CAE11EC86D3BF1FBE471BE89827E19D72AA1D")
+	message := []byte("off-chain payload")
+	sig, err := Sign(privKey, message)
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	first := sha256.Sum256(message)
+	if !VerifyHash(Public(privKey, true), sha256.Sum256(first[:]), sig) {
+		t.Errorf("signature should be valid for the double sha256 of the message")
+	}
+	if VerifyHash(Public(privKey, true), first, sig) {
+		t.Errorf("signature should not be valid for the single sha256 of the message")
+	}
+	again, _ := Sign(privKey, message)
+	if hex.EncodeToString(again) != hex.EncodeToString(sig) {
+		t.Errorf("signature should be deterministic")
+	}
+	if _, err := Sign(make([]byte, 32), message); err == nil {
+		t.Errorf("zero key should return an error")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	privKey, _ := hex.DecodeString("0C28FCA386C7A227600B2FE50B7CAE11EC86D3BF1FBE471BE89827E19D72AA1D")
+	message := []byte("off-chain payload")
+	sig, _ := Sign(privKey, message)
+	for _, compressed := range []bool{true, false} {
+		pubKey := Public(privKey, compressed)
+		valid, err := Verify(pubKey, message, sig)
+		if err != nil || !valid {
+			t.Errorf("signature should be valid for public key %x, got %v, %v", pubKey, valid, err)
+		}
+		valid, err = Verify(pubKey, []byte("off-chain payload!"), sig)
+		if err != nil || valid {
+			t.Errorf("signature should not be valid for a tampered message, got %v, %v", valid, err)
+		}
+	}
+	if _, err := Verify([]byte{0x02}, message, sig); err == nil {
+		t.Errorf("malformed public key should return an error")
+	}
+	if _, err := Verify(Public(privKey, true), message, sig[:len(sig)-1]); err == nil {
+		t.Errorf("malformed signature should return an error")
+	}
+}
+
+func TestRecoverPubKey(t *testing.T) {
+	privKey, _ := hex.DecodeString("0C28FCA386C7A227600B2FE50B7CAE11EC86D3BF1FBE471BE89827E19D72AA1D")
+	message := []byte("off-chain payload")
+	for _, compressed := range []bool{true, false} {
+		sig, err := SignCompact(privKey, message, compressed)
+		if err != nil {
+			t.Fatalf("failed due to %v\n", err)
+		}
+		pubKey, recoveredCompressed, err := RecoverPubKey(sig, message)
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+			continue
+		}
+		if !bytes.Equal(pubKey, Public(privKey, compressed)) || recoveredCompressed != compressed {
+			t.Errorf("recovered public key should be %x but is %x", Public(privKey, compressed), pubKey)
+		}
+		other, _, err := RecoverPubKey(sig, []byte("another payload"))
+		if err == nil && bytes.Equal(other, pubKey) {
+			t.Errorf("another message should not recover the same public key")
+		}
+	}
+	if _, _, err := RecoverPubKey(make([]byte, 64), message); err == nil {
+		t.Errorf("64 bytes signature should return an error")
+	}
+	if _, err := SignCompact(make([]byte, 32), message, true); err == nil {
+		t.Errorf("zero key should return an error")
+	}
+}
@@ -0,0 +1,120 @@
+package keys
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/savardiego/cashline/segwit"
+)
+
+// TaprootInternalKeyToWIF returns the WIF of the key spending a BIP86 Taproot output (key path only, no script tree)
+// of an internal private key. BIP340 public keys are x-only, i.e. their Y coordinate is always even:
+// when the internal public key has an odd Y the private key is first negated (n - d), then the tweak
+// tagged_hash("TapTweak", x(P)) is added modulo n.
+// Reference: https://github.com/bitcoin/bips/blob/master/bip-0341.mediawiki#constructing-and-spending-taproot-outputs
+func TaprootInternalKeyToWIF(internalPriv []byte, compressed bool, network Network) (string, error) {
+	tweaked, err := taprootTweakPrivate(internalPriv)
+	if err != nil {
+		return "", err
+	}
+	return ToWIFNetwork(tweaked, compressed, network)
+}
+
+// TaprootOutputKey returns the 32 bytes x-only output key of a Taproot output with the given internal public key
+// (33 bytes compressed or 32 bytes x-only) and script tree merkle root (nil or empty for key path only outputs, as BIP86)
+func TaprootOutputKey(internalPubKey []byte, merkleRoot []byte) ([]byte, error) {
+	if len(internalPubKey) == 33 {
+		internalPubKey = internalPubKey[1:]
+	}
+	if len(internalPubKey) != 32 {
+		return nil, fmt.Errorf("internal public key is %d bytes long, must be 32 (x-only) or 33 (compressed)", len(internalPubKey))
+	}
+	if len(merkleRoot) != 0 && len(merkleRoot) != 32 {
+		return nil, fmt.Errorf("merkle root is %d bytes long, must be 32 or empty", len(merkleRoot))
+	}
+	curve := btcec.S256()
+	// lift_x: the point with this x and even y
+	internal, err := btcec.ParsePubKey(append([]byte{0x02}, internalPubKey...), curve)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse internal public key due to %v", err)
+	}
+	tweak := taggedHash("TapTweak", internalPubKey, merkleRoot)
+	if new(big.Int).SetBytes(tweak).Cmp(curve.N) >= 0 {
+		return nil, errors.New("taproot tweak is out of range")
+	}
+	tx, ty := curve.ScalarBaseMult(tweak)
+	x, y := curve.Add(internal.X, internal.Y, tx, ty)
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return nil, errors.New("taproot output key is the point at infinity")
+	}
+	return x.FillBytes(make([]byte, 32)), nil
+}
+
+// AddressP2TRScriptPath returns the bech32m Taproot address committing to a script tree: the internal public key (33 bytes compressed
+// or 32 bytes x-only) is tweaked with the 32 bytes merkle root of the tree as BIP341 describes. An empty merkle root gives
+// the key path only address of BIP86
+func AddressP2TRScriptPath(internalPubKey []byte, merkleRoot []byte, network Network) (string, error) {
+	if network.String() == "unknown" {
+		return "", fmt.Errorf("unknown network %d", network)
+	}
+	outputKey, err := TaprootOutputKey(internalPubKey, merkleRoot)
+	if err != nil {
+		return "", err
+	}
+	return segwit.Encode(network.Bech32HRP(), 1, outputKey)
+}
+
+// TaprootInternalKey returns the 32 bytes x-only internal public key of a private key and the parity of its Y (0 even, 1 odd).
+// BIP340 keys are lifted to the point with even Y, so a parity of 1 means the private key must be negated (n - d) before tweaking or signing.
+// In a script path spend the control block carries the x-only internal key after a first byte made of the leaf version
+// and, in its lowest bit, the Y parity of the tweaked output key Q (not of the internal key): P alone does not give the control byte
+func TaprootInternalKey(privKey []byte) (xOnly []byte, parity byte, err error) {
+	if len(privKey) != 32 {
+		return nil, 0, fmt.Errorf("private key is %d bytes long, must be 32", len(privKey))
+	}
+	if !isValidKey(new(big.Int).SetBytes(privKey)) {
+		return nil, 0, errors.New("input represents a number not acceptable as private key")
+	}
+	x, y := btcec.S256().ScalarBaseMult(privKey)
+	return x.FillBytes(make([]byte, 32)), byte(y.Bit(0)), nil
+}
+
+// taprootTweakPrivate returns the 32 bytes private key of the output key committing to no script tree
+func taprootTweakPrivate(internalPriv []byte) ([]byte, error) {
+	if len(internalPriv) != 32 {
+		return nil, fmt.Errorf("internal private key is %d bytes long, must be 32", len(internalPriv))
+	}
+	d := new(big.Int).SetBytes(internalPriv)
+	if !isValidKey(d) {
+		return nil, errors.New("internal private key is not acceptable as private key")
+	}
+	curve := btcec.S256()
+	x, y := curve.ScalarBaseMult(internalPriv)
+	if y.Bit(0) == 1 {
+		d.Sub(curve.N, d)
+	}
+	t := new(big.Int).SetBytes(taggedHash("TapTweak", x.FillBytes(make([]byte, 32))))
+	if t.Cmp(curve.N) >= 0 {
+		return nil, errors.New("taproot tweak is out of range")
+	}
+	d.Add(d, t).Mod(d, curve.N)
+	if d.Sign() == 0 {
+		return nil, errors.New("tweaked private key is zero")
+	}
+	return d.FillBytes(make([]byte, 32)), nil
+}
+
+// taggedHash returns the BIP340 tagged hash sha256(sha256(tag) || sha256(tag) || msg)
+func taggedHash(tag string, msg ...[]byte) []byte {
+	tagHash := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	for _, m := range msg {
+		h.Write(m)
+	}
+	return h.Sum(nil)
+}
@@ -0,0 +1,144 @@
+package keys
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+)
+
+func TestTaprootInternalKeyToWIF(t *testing.T) {
+	// Internal private key, compressed, network, tweaked WIF
+	expected := [][]string{
+		[]string{"0000000000000000000000000000000000000000000000000000000000000001", "true", "mainnet", "KyGComs4RrHnqnn5ku3WxA6CVFmHJV61bm8V36Uu4WMsRjjdeAVr"},
+		[]string{"0000000000000000000000000000000000000000000000000000000000000001", "false", "testnet", "923mEycKmqi28knPgwo917FZRaYebsnvcyhmfdgy68FhV5WnE9q"},
+		// odd Y internal public key, negated before tweaking
+		[]string{"0000000000000000000000000000000000000000000000000000000000000006", "true", "mainnet", "KxVZyhFvcyBJGeMmUeVmr3WMdcRD1rKWKWsD6BSDZoaQaAijYJJo"},
+		[]string{"0C28FCA386C7A227600B2FE50B7CAE11EC86D3BF1FBE471BE89827E19D72AA1D", "true", "mainnet", "L5GsFtFvmdPrVo3birp4wWjXN5JKWZxCxatFrmmitfYGhXLChf3Q"},
+		[]string{"18e14a7b6a307f426a94f8114701e7c8e774e7f9a47e2c2035db29a206321725", "true", "mainnet", "KyfaV215VqKQdYNXFp3GmCQts8Usu96k76UqgJLn1Fka9MGUt3Ss"},
+	}
+	for _, v := range expected {
+		privKey, _ := hex.DecodeString(v[0])
+		network := MainNet
+		if v[2] == "testnet" {
+			network = TestNet
+		}
+		wif, err := TaprootInternalKeyToWIF(privKey, v[1] == "true", network)
+		if err != nil {
+			t.Errorf("cannot tweak key %v due to %v", v[0], err)
+			continue
+		}
+		if wif != v[3] {
+			t.Errorf("tweaked WIF of %v should be %v but is %v", v[0], v[3], wif)
+		}
+	}
+	wrong := []string{
+		"",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141",
+		"00000000000000000000000000000000000000000000000000000000000001",
+	}
+	for _, w := range wrong {
+		privKey, _ := hex.DecodeString(w)
+		if _, err := TaprootInternalKeyToWIF(privKey, true, MainNet); err == nil {
+			t.Errorf("internal key %v should not be accepted", w)
+		}
+	}
+}
+
+func TestTaprootOutputKey(t *testing.T) {
+	// https://github.com/bitcoin/bips/blob/master/bip-0086.mediawiki#test-vectors
+	expected := [][]string{
+		[]string{"cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115", "a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c"},
+		[]string{"03cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115", "a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c"},
+		[]string{"83dfe85a3151d2517290da461fe2815591ef69f2b18a2ce63f01697a8b313145", "a82f29944d65b86ae6b5e5cc75e294ead6c59391a1edc5e016e3498c67fc7bbb"},
+	}
+	for _, v := range expected {
+		internalKey, _ := hex.DecodeString(v[0])
+		outputKey, err := TaprootOutputKey(internalKey, nil)
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+			continue
+		}
+		if hex.EncodeToString(outputKey) != v[1] {
+			t.Errorf("output key of %v should be %v but is %x", v[0], v[1], outputKey)
+		}
+	}
+	internalKey, _ := hex.DecodeString(expected[0][0])
+	if _, err := TaprootOutputKey(internalKey, make([]byte, 31)); err == nil {
+		t.Errorf("short merkle root should return an error")
+	}
+	if _, err := TaprootOutputKey(internalKey[1:], nil); err == nil {
+		t.Errorf("short internal key should return an error")
+	}
+}
+
+func TestAddressP2TRScriptPath(t *testing.T) {
+	// https://github.com/bitcoin/bips/blob/master/bip-0341/wallet-test-vectors.json and BIP86 (no merkle root)
+	expected := [][]string{
+		[]string{"187791b6f712a8ea41c8ecdd0ee77fab3e85263b37e1ec18a3651926b3a6cf27", "5b75adecf53548f3ec6ad7d78383bf84cc57b55a3127c72b9a2481752dd88b21", "bc1pz37fc4cn9ah8anwm4xqqhvxygjf9rjf2resrw8h8w4tmvcs0863sa2e586"},
+		[]string{"cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115", "", "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr"},
+	}
+	for _, v := range expected {
+		internalKey, _ := hex.DecodeString(v[0])
+		merkleRoot, _ := hex.DecodeString(v[1])
+		address, err := AddressP2TRScriptPath(internalKey, merkleRoot, MainNet)
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+			continue
+		}
+		if address != v[2] {
+			t.Errorf("address of %v with merkle root %v should be %v but is %v", v[0], v[1], v[2], address)
+		}
+	}
+	internalKey, _ := hex.DecodeString(expected[0][0])
+	if _, err := AddressP2TRScriptPath(internalKey, make([]byte, 20), MainNet); err == nil {
+		t.Errorf("20 bytes merkle root should return an error")
+	}
+	if _, err := AddressP2TRScriptPath(internalKey, nil, Network(9)); err == nil {
+		t.Errorf("unknown network should return an error")
+	}
+}
+
+func TestTaggedHash(t *testing.T) {
+	// https://github.com/bitcoin/bips/blob/master/bip-0086.mediawiki#test-vectors
+	// the output key is lift_x(internal key) + TapTweak(internal key) * G
+	internalKey, _ := hex.DecodeString("cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115")
+	expected := "a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c"
+	curve := btcec.S256()
+	internal, err := btcec.ParsePubKey(append([]byte{0x02}, internalKey...), curve)
+	if err != nil {
+		t.Fatalf("cannot lift internal key due to %v", err)
+	}
+	tx, ty := curve.ScalarBaseMult(taggedHash("TapTweak", internalKey))
+	x, _ := curve.Add(internal.X, internal.Y, tx, ty)
+	if outputKey := hex.EncodeToString(x.FillBytes(make([]byte, 32))); outputKey != expected {
+		t.Errorf("output key of %x should be %v but is %v", internalKey, expected, outputKey)
+	}
+}
+
+func TestTaprootInternalKey(t *testing.T) {
+	// Private key, x-only internal key, parity
+	expected := [][]string{
+		[]string{"0000000000000000000000000000000000000000000000000000000000000001", "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", "0"},
+		[]string{"0000000000000000000000000000000000000000000000000000000000000006", "fff97bd5755eeea420453a14355235d382f6472f8568a18b2f057a1460297556", "1"},
+		[]string{"0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d", "d0de0aaeaefad02b8bdc8a01a1b8b11c696bd3d66a2c5f10780d95b7df42645c", "0"},
+	}
+	for _, v := range expected {
+		privKey, _ := hex.DecodeString(v[0])
+		xOnly, parity, err := TaprootInternalKey(privKey)
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+			continue
+		}
+		if hex.EncodeToString(xOnly) != v[1] || string('0'+parity) != v[2] {
+			t.Errorf("expected %v with parity %v, got %x with parity %d", v[1], v[2], xOnly, parity)
+		}
+	}
+	if _, _, err := TaprootInternalKey(make([]byte, 32)); err == nil {
+		t.Errorf("zero key should return an error")
+	}
+	if _, _, err := TaprootInternalKey(make([]byte, 31)); err == nil {
+		t.Errorf("short key should return an error")
+	}
+}
@@ -0,0 +1,45 @@
+package keys
+
+import (
+	"errors"
+	"math/big"
+)
+
+// Wallet holds a private key with its WIF, public key and P2PKH address, all derived with the same compression and network
+type Wallet struct {
+	PrivateKey []byte
+	WIF        string
+	PublicKey  []byte
+	Address    string
+	Compressed bool
+	Testnet    bool
+}
+
+// NewWallet derives in one pass the WIF, the public key and the P2PKH address of a private key,
+// so that a compressed WIF is never paired with the address of the uncompressed public key or the other way around
+func NewWallet(privKey []byte, compressed, testnet bool) (*Wallet, error) {
+	if len(privKey) != 32 || !isValidKey(new(big.Int).SetBytes(privKey)) {
+		return nil, errors.New("given key is not acceptable as private key")
+	}
+	network := MainNet
+	if testnet {
+		network = TestNet
+	}
+	wif, err := ToWIFNetwork(privKey, compressed, network)
+	if err != nil {
+		return nil, err
+	}
+	pubKey := Public(privKey, compressed)
+	address, err := Address(Hashed(pubKey), testnet)
+	if err != nil {
+		return nil, err
+	}
+	return &Wallet{
+		PrivateKey: append([]byte{}, privKey...),
+		WIF:        wif,
+		PublicKey:  pubKey,
+		Address:    address,
+		Compressed: compressed,
+		Testnet:    testnet,
+	}, nil
+}
@@ -0,0 +1,44 @@
+package keys
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestNewWallet(t *testing.T) {
+	privKey, _ := hex.DecodeString("0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d")
+	// Compressed, testnet, WIF, address
+	expected := [][]string{
+		[]string{"false", "false", "5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ", "1GAehh7TsJAHuUAeKZcXf5CnwuGuGgyX2S"},
+		[]string{"true", "false", "KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617", "1LoVGDgRs9hTfTNJNuXKSpywcbdvwRXpmK"},
+		[]string{"true", "true", "cMzLdeGd5vEqxB8B6VFQoRopQ3sLAAvEzDAoQgvX54xwofSWj1fx", "n1KSZGmQgB8iSZqv6UVhGkCGUbEdw8Lm3Q"},
+	}
+	for _, v := range expected {
+		compressed, testnet := v[0] == "true", v[1] == "true"
+		wallet, err := NewWallet(privKey, compressed, testnet)
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+			continue
+		}
+		if wallet.WIF != v[2] || wallet.Address != v[3] {
+			t.Errorf("wallet should have WIF %v and address %v, got %v and %v", v[2], v[3], wallet.WIF, wallet.Address)
+		}
+		if !bytes.Equal(wallet.PrivateKey, privKey) || wallet.Compressed != compressed || wallet.Testnet != testnet {
+			t.Errorf("unexpected wallet %+v", wallet)
+		}
+		decoded, wifCompressed, wifTestnet, err := PrivateFromWIF(wallet.WIF)
+		if err != nil || !bytes.Equal(decoded, privKey) || wifCompressed != compressed || wifTestnet != testnet {
+			t.Errorf("WIF %v does not match the wallet", wallet.WIF)
+		}
+		if address, _ := Address(Hashed(wallet.PublicKey), testnet); address != wallet.Address {
+			t.Errorf("public key %x does not match address %v", wallet.PublicKey, wallet.Address)
+		}
+		if expectedLength := map[bool]int{true: 33, false: 65}[compressed]; len(wallet.PublicKey) != expectedLength {
+			t.Errorf("public key should be %d bytes, is %d", expectedLength, len(wallet.PublicKey))
+		}
+	}
+	if _, err := NewWallet(make([]byte, 32), true, false); err == nil {
+		t.Errorf("zero key should return an error")
+	}
+}
@@ -0,0 +1,11 @@
+package keys
+
+import (
+	"runtime"
+)
+
+// OptimalWorkerCount returns the recommended number of goroutines for parallel key derivation (vanity search, address scans, batches).
+// Derivation is pure CPU work with no I/O to overlap, so more workers than usable CPUs (GOMAXPROCS) only add scheduling overhead
+func OptimalWorkerCount() int {
+	return runtime.GOMAXPROCS(0)
+}
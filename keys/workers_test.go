@@ -0,0 +1,13 @@
+package keys
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestOptimalWorkerCount(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(2))
+	if workers := OptimalWorkerCount(); workers != 2 {
+		t.Errorf("worker count should follow GOMAXPROCS 2 but is %d", workers)
+	}
+}
@@ -1,21 +1,39 @@
 package legacy
 
 import (
+	"bytes"
 	"crypto/sha256"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"github.com/btcsuite/btcd/btcec"
 	"github.com/btcsuite/btcutil/base58"
 	"github.com/savardiego/cashline/keys"
 	"reflect"
+	"sync"
 )
 
+// messageMagic is prepended to messages before hashing them for signing, so that a signature cannot be reused as a transaction signature
+const messageMagic = "Bitcoin Signed Message:\n"
+
+// NetworkForm holds the WIF and the P2PKH address of a private key on a network
+type NetworkForm struct {
+	WIF     string
+	Address string
+}
+
 // FromPubKey derivates a legacy address (version 1, the oldest) from a public key
 func FromPubKey(pubKey []byte) (string, error) {
 	hashed := keys.Hashed(pubKey)
-	withVersion := append([]byte{0x00}, hashed...)
+	return fromHash(keys.MainNet.PubKeyHashVersion(), hashed), nil
+}
+
+// fromHash encodes an hash with its version byte and checksum in base58
+func fromHash(version byte, hash []byte) string {
+	withVersion := append([]byte{version}, hash...)
 	checkSum := checksum(withVersion)
 	withVersionAndChecksum := append(withVersion, checkSum...)
-	address := base58.Encode(withVersionAndChecksum)
-	return address, nil
+	return base58.Encode(withVersionAndChecksum)
 }
 
 func checksum(hashWithVer []byte) []byte {
@@ -41,14 +59,187 @@ func CheckAddress(address string) bool {
 	return reflect.DeepEqual(check, checksumPart)
 }
 
-// FromWIF derivates a legacy address (version 1, the oldest) from a base58 encoded WIF private key, compressed/uncompressed depending on the WIF format.
+// FromWIF derivates a legacy address (version 1, the oldest) from a base58 encoded WIF private key, compressed/uncompressed depending on the WIF format,
+// on mainnet or testnet depending on the WIF version
 func FromWIF(privKey string) (string, error) {
-	decodedPrivKey, compressed, err := keys.PrivateFromWIF(privKey)
+	decodedPrivKey, compressed, testnet, err := keys.PrivateFromWIF(privKey)
 	if err != nil {
 		fmt.Printf("Cannot decode private key from base58 string: %v", privKey)
 		return "", fmt.Errorf("Cannot decode private key from base58 string: %v due to %v", privKey, err)
 	}
 	publicKey := keys.Public(decodedPrivKey, compressed)
+	if testnet {
+		return fromHash(keys.TestNet.PubKeyHashVersion(), keys.Hashed(publicKey)), nil
+	}
 	address, err := FromPubKey(publicKey)
 	return address, err
 }
+
+// AllNetworkForms returns, for every supported network (by name), the WIF and the P2PKH address of a private key
+func AllNetworkForms(privKey []byte, compressed bool) (map[string]NetworkForm, error) {
+	hashed := keys.Hashed(keys.Public(privKey, compressed))
+	forms := make(map[string]NetworkForm)
+	for _, network := range keys.Networks {
+		wif, err := keys.ToWIFNetwork(privKey, compressed, network)
+		if err != nil {
+			return nil, fmt.Errorf("cannot encode WIF for %v due to %v", network, err)
+		}
+		forms[network.String()] = NetworkForm{WIF: wif, Address: fromHash(network.PubKeyHashVersion(), hashed)}
+	}
+	return forms, nil
+}
+
+// VerifyMessageForAddress verifies a base64 compact signature of a message in the Bitcoin Signed Message format (as produced by signmessage):
+// the public key is recovered from the signature and its P2PKH address on the given network is compared with the expected address
+func VerifyMessageForAddress(address, message, signatureBase64 string, network keys.Network) (bool, error) {
+	pubKeyBytes, segwitHeader, err := recoverMessagePubKey(message, signatureBase64)
+	if err != nil {
+		return false, err
+	}
+	if segwitHeader {
+		return false, fmt.Errorf("signature header is for a segwit address (BIP137), %v is a P2PKH address", address)
+	}
+	recovered := fromHash(network.PubKeyHashVersion(), keys.Hashed(pubKeyBytes))
+	return recovered == address, nil
+}
+
+// SignMessage signs a message in the Bitcoin Signed Message format (as signmessage of Bitcoin Core and Electrum), returning the base64
+// compact signature. Its header tells verifiers to recover the compressed or the uncompressed public key, so it must match the address
+func SignMessage(privKey []byte, message string, compressed bool) (string, error) {
+	if len(privKey) != 32 {
+		return "", fmt.Errorf("private key is %d bytes long, must be 32", len(privKey))
+	}
+	key, _ := btcec.PrivKeyFromBytes(btcec.S256(), privKey)
+	if key.D.Sign() == 0 || key.D.Cmp(btcec.S256().N) >= 0 {
+		return "", errors.New("given key is not acceptable as private key")
+	}
+	signature, err := btcec.SignCompact(btcec.S256(), key, messageHash(message), compressed)
+	if err != nil {
+		return "", fmt.Errorf("cannot sign message due to %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+// VerifyMessage verifies a base64 compact signature of a message in the Bitcoin Signed Message format against a P2PKH address,
+// mainnet or testnet according to its version byte
+func VerifyMessage(address, message, signatureBase64 string) (bool, error) {
+	_, version, err := base58.CheckDecode(address)
+	if err != nil {
+		return false, fmt.Errorf("cannot decode address %v due to %v", address, err)
+	}
+	for _, network := range []keys.Network{keys.MainNet, keys.TestNet} {
+		if version == network.PubKeyHashVersion() {
+			return VerifyMessageForAddress(address, message, signatureBase64, network)
+		}
+	}
+	return false, fmt.Errorf("address %v is not a P2PKH address, version 0x%02x", address, version)
+}
+
+// VerifyItem is a public key, a message and its base64 compact signature to be verified by VerifyMessagesBatch
+type VerifyItem struct {
+	PubKey    []byte
+	Message   string
+	Signature string
+}
+
+// VerifyMessagesBatch verifies many message signatures in parallel: each result is true only if the signature
+// of the item with the same index is well formed and recovers to its public key
+func VerifyMessagesBatch(items []VerifyItem) []bool {
+	results := make([]bool, len(items))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	workers := keys.OptimalWorkerCount()
+	if workers > len(items) {
+		workers = len(items)
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				pubKey, _, err := recoverMessagePubKey(items[i].Message, items[i].Signature)
+				results[i] = err == nil && bytes.Equal(pubKey, items[i].PubKey)
+			}
+		}()
+	}
+	for i := range items {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
+
+// recoverMessagePubKey returns the serialized public key recovered from a base64 compact signature of a message,
+// compressed or uncompressed according to the signature header, and whether the header is one of a segwit address (BIP137)
+func recoverMessagePubKey(message, signatureBase64 string) (pubKey []byte, segwitHeader bool, err error) {
+	signature, err := base64.StdEncoding.DecodeString(signatureBase64)
+	if err != nil {
+		return nil, false, fmt.Errorf("cannot decode signature from base64 due to %v", err)
+	}
+	normalized, err := NormalizeCompactSignature(signature)
+	if err != nil {
+		return nil, false, err
+	}
+	recovered, compressed, err := btcec.RecoverCompact(btcec.S256(), normalized, messageHash(message))
+	if err != nil {
+		return nil, false, fmt.Errorf("cannot recover public key from signature due to %v", err)
+	}
+	segwitHeader = signature[0] >= 35
+	if compressed {
+		return recovered.SerializeCompressed(), segwitHeader, nil
+	}
+	return recovered.SerializeUncompressed(), segwitHeader, nil
+}
+
+// NormalizeCompactSignature checks a 65 bytes compact signature (header + R + S) and returns it with the canonical header of Bitcoin Core,
+// 27 + recovery id (0-3) + 4 if the key is compressed, the form btcec.RecoverCompact reads. BIP137 adds 4 more for P2SH-P2WPKH (35-38)
+// and 8 more for P2WPKH (39-42) addresses, whose keys are always compressed: the normalized header keeps the recovery id and
+// the compression flag but drops the address type, so callers checking an address must read it from the original header
+func NormalizeCompactSignature(sig []byte) ([]byte, error) {
+	if len(sig) != 65 {
+		return nil, fmt.Errorf("compact signature is %d bytes long, must be 65", len(sig))
+	}
+	header := sig[0]
+	if header < 27 || header > 42 {
+		return nil, fmt.Errorf("compact signature header %d out of range 27-42", header)
+	}
+	recoveryID := (header - 27) % 4
+	compressed := header >= 31
+	normalized := append([]byte{27 + recoveryID}, sig[1:]...)
+	if compressed {
+		normalized[0] += 4
+	}
+	return normalized, nil
+}
+
+// messageHash returns the double sha256 of the message prefixed by the magic string, both preceded by their length as varint
+func messageHash(message string) []byte {
+	var buf bytes.Buffer
+	writeVarInt(&buf, uint64(len(messageMagic)))
+	buf.WriteString(messageMagic)
+	writeVarInt(&buf, uint64(len(message)))
+	buf.WriteString(message)
+	hashOne := sha256.Sum256(buf.Bytes())
+	hashTwo := sha256.Sum256(hashOne[:])
+	return hashTwo[:]
+}
+
+// writeVarInt writes a bitcoin variable length integer
+func writeVarInt(buf *bytes.Buffer, n uint64) {
+	switch {
+	case n < 0xfd:
+		buf.WriteByte(byte(n))
+	case n <= 0xffff:
+		buf.WriteByte(0xfd)
+		buf.Write([]byte{byte(n), byte(n >> 8)})
+	case n <= 0xffffffff:
+		buf.WriteByte(0xfe)
+		buf.Write([]byte{byte(n), byte(n >> 8), byte(n >> 16), byte(n >> 24)})
+	default:
+		buf.WriteByte(0xff)
+		for i := uint(0); i < 8; i++ {
+			buf.WriteByte(byte(n >> (8 * i)))
+		}
+	}
+}
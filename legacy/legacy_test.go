@@ -1,8 +1,12 @@
 package legacy
 
 import (
+	"bytes"
+	"encoding/base64"
 	"encoding/hex"
 	"testing"
+
+	"github.com/savardiego/cashline/keys"
 )
 
 func TestUncompressedV1FromPubKey(t *testing.T) {
@@ -136,3 +140,189 @@ func TestBulkUncompressedWIF(t *testing.T) {
 		}
 	}
 }
+
+func TestTestnetWIF(t *testing.T) {
+	// Address, testnet WIF
+	keyAddress := [][]string{
+		[]string{"n1KSZGmQgB8iSZqv6UVhGkCGUbEdw8Lm3Q", "cMzLdeGd5vEqxB8B6VFQoRopQ3sLAAvEzDAoQgvX54xwofSWj1fx"},
+		[]string{"mvgbzkCSgKbYgaeG38auUzR7otscEGi8U7", "91gGn1HgSap6CbU12F6z3pJri26xzp7Ay1VW6NHCoEayNXwRpu2"},
+	}
+	for _, pair := range keyAddress {
+		address, err := FromWIF(pair[1])
+		if err != nil {
+			t.Errorf("Unexpected error while decoding address: %v", err)
+		}
+		if address != pair[0] {
+			t.Errorf("Decoded address was not the expected expected: %v, decoded: %v from key %v", pair[0], address, pair[1])
+		}
+	}
+}
+
+func TestAllNetworkForms(t *testing.T) {
+	privKeyHexString := "0C28FCA386C7A227600B2FE50B7CAE11EC86D3BF1FBE471BE89827E19D72AA1D"
+	privKeyByte, err := hex.DecodeString(privKeyHexString)
+	if err != nil {
+		t.Errorf("Unexpected error while decoding key: %v", err)
+	}
+	// Compressed, network, WIF, address
+	expected := [][]string{
+		[]string{"true", "mainnet", "KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617", "1LoVGDgRs9hTfTNJNuXKSpywcbdvwRXpmK"},
+		[]string{"true", "testnet", "cMzLdeGd5vEqxB8B6VFQoRopQ3sLAAvEzDAoQgvX54xwofSWj1fx", "n1KSZGmQgB8iSZqv6UVhGkCGUbEdw8Lm3Q"},
+		[]string{"false", "mainnet", "5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ", "1GAehh7TsJAHuUAeKZcXf5CnwuGuGgyX2S"},
+		[]string{"false", "testnet", "91gGn1HgSap6CbU12F6z3pJri26xzp7Ay1VW6NHCoEayNXwRpu2", "mvgbzkCSgKbYgaeG38auUzR7otscEGi8U7"},
+	}
+	for _, v := range expected {
+		forms, err := AllNetworkForms(privKeyByte, v[0] == "true")
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+		}
+		form, ok := forms[v[1]]
+		if !ok {
+			t.Errorf("missing %v in network forms %v", v[1], forms)
+			continue
+		}
+		if form.WIF != v[2] || form.Address != v[3] {
+			t.Errorf("%v form (compressed %v) should be %v %v but is %v %v", v[1], v[0], v[2], v[3], form.WIF, form.Address)
+		}
+	}
+}
+
+func TestVerifyMessageForAddress(t *testing.T) {
+	// https://github.com/bitcoinjs/bitcoinjs-message
+	address := "1F3sAm6ZtwLAUnj7d38pGFxtP3RVEvtsbV"
+	message := "This is an example of a signed message."
+	signature := "H9L5yLFjti0QTHhPyFrZCT1V/MMnBtXKmoiKDZ78NDBjERki6ZTQZdSMCtkgoNmp17By9ItJr8o7ChX0XxY91nk="
+	valid, err := VerifyMessageForAddress(address, message, signature, keys.MainNet)
+	if err != nil {
+		t.Errorf("failed due to %v\n", err)
+	}
+	if !valid {
+		t.Errorf("signature of %v should be valid for %v", message, address)
+	}
+	valid, err = VerifyMessageForAddress(address, message+"!", signature, keys.MainNet)
+	if err != nil {
+		t.Errorf("failed due to %v\n", err)
+	}
+	if valid {
+		t.Errorf("signature of %v should not be valid for a tampered message", message)
+	}
+	valid, err = VerifyMessageForAddress(address, message, signature, keys.TestNet)
+	if err != nil {
+		t.Errorf("failed due to %v\n", err)
+	}
+	if valid {
+		t.Errorf("signature for mainnet address %v should not be valid on testnet", address)
+	}
+	if _, err = VerifyMessageForAddress(address, message, "not base64!", keys.MainNet); err == nil {
+		t.Errorf("malformed signature should return an error")
+	}
+	if _, err = VerifyMessageForAddress(address, message, "H9L5yLFjti0QTHhP", keys.MainNet); err == nil {
+		t.Errorf("short signature should return an error")
+	}
+}
+
+func TestSignMessage(t *testing.T) {
+	// https://github.com/bitcoinjs/bitcoinjs-message
+	privKey, _, _, _ := keys.PrivateFromWIF("5KYZdUEo39z3FPrtuX2QbbwGnNP5zTd7yyr2SC1j299sBCnWjss")
+	message := "This is an example of a signed message."
+	signature, err := SignMessage(privKey, message, true)
+	if err != nil {
+		t.Fatalf("failed due to %v\n", err)
+	}
+	expected := "H9L5yLFjti0QTHhPyFrZCT1V/MMnBtXKmoiKDZ78NDBjERki6ZTQZdSMCtkgoNmp17By9ItJr8o7ChX0XxY91nk="
+	if signature != expected {
+		t.Errorf("signature should be %v but is %v", expected, signature)
+	}
+	for _, compressed := range []bool{true, false} {
+		for _, testnet := range []bool{false, true} {
+			address, _ := keys.AddressFromPrivate(privKey, compressed, testnet)
+			signature, _ := SignMessage(privKey, message, compressed)
+			if valid, err := VerifyMessage(address, message, signature); err != nil || !valid {
+				t.Errorf("signature should be valid for %v, got %v", address, err)
+			}
+			if valid, _ := VerifyMessage(address, message+"!", signature); valid {
+				t.Errorf("signature should not be valid for a tampered message")
+			}
+		}
+	}
+	uncompressed, _ := keys.AddressFromPrivate(privKey, false, false)
+	if valid, _ := VerifyMessage(uncompressed, message, expected); valid {
+		t.Errorf("compressed signature should not be valid for the uncompressed address")
+	}
+	if _, err := VerifyMessage("3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", message, expected); err == nil {
+		t.Errorf("P2SH address should return an error")
+	}
+	if _, err := SignMessage(make([]byte, 32), message, true); err == nil {
+		t.Errorf("zero key should return an error")
+	}
+}
+
+func TestVerifyMessagesBatch(t *testing.T) {
+	// https://github.com/bitcoinjs/bitcoinjs-message
+	pubKey, _ := hex.DecodeString("03a34b99f22c790c4e36b2b3c2c35a36db06226e41c692fc82b8b56ac1c540c5bd")
+	otherPubKey, _ := hex.DecodeString("0250863ad64a87ae8a2fe83c1af1a8403cb53f53e486d8511dad8a04887e5b2352")
+	message := "This is an example of a signed message."
+	signature := "H9L5yLFjti0QTHhPyFrZCT1V/MMnBtXKmoiKDZ78NDBjERki6ZTQZdSMCtkgoNmp17By9ItJr8o7ChX0XxY91nk="
+	items := []VerifyItem{
+		{pubKey, message, signature},
+		{pubKey, message + "!", signature},
+		{otherPubKey, message, signature},
+		{pubKey, message, "not base64!"},
+		{pubKey, message, signature},
+	}
+	expected := []bool{true, false, false, false, true}
+	results := VerifyMessagesBatch(items)
+	if len(results) != len(expected) {
+		t.Fatalf("got %d results, expected %d", len(results), len(expected))
+	}
+	for i := range expected {
+		if results[i] != expected[i] {
+			t.Errorf("item %d: got %v, expected %v", i, results[i], expected[i])
+		}
+	}
+	if results := VerifyMessagesBatch(nil); len(results) != 0 {
+		t.Errorf("empty batch should return no results")
+	}
+}
+
+func TestNormalizeCompactSignature(t *testing.T) {
+	signature, _ := base64.StdEncoding.DecodeString("H9L5yLFjti0QTHhPyFrZCT1V/MMnBtXKmoiKDZ78NDBjERki6ZTQZdSMCtkgoNmp17By9ItJr8o7ChX0XxY91nk=")
+	// Header, expected normalized header (0 if an error is expected)
+	expected := [][]byte{
+		{27, 27}, {30, 30}, {31, 31}, {34, 34},
+		{35, 31}, {38, 34}, {39, 31}, {42, 34},
+		{26, 0}, {43, 0}, {0, 0}, {3, 0},
+	}
+	for _, v := range expected {
+		sig := append([]byte{v[0]}, signature[1:]...)
+		normalized, err := NormalizeCompactSignature(sig)
+		if v[1] == 0 {
+			if err == nil {
+				t.Errorf("header %d should return an error", v[0])
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("failed due to %v\n", err)
+			continue
+		}
+		if normalized[0] != v[1] || !bytes.Equal(normalized[1:], sig[1:]) {
+			t.Errorf("header %d should be normalized to %d but is %d", v[0], v[1], normalized[0])
+		}
+	}
+	if _, err := NormalizeCompactSignature(signature[:64]); err == nil {
+		t.Errorf("short signature should return an error")
+	}
+	// the same signature with the header Electrum uses for P2WPKH is not a signature for a P2PKH address
+	electrum := base64.StdEncoding.EncodeToString(append([]byte{signature[0] + 8}, signature[1:]...))
+	valid, err := VerifyMessageForAddress("1F3sAm6ZtwLAUnj7d38pGFxtP3RVEvtsbV", "This is an example of a signed message.", electrum, keys.MainNet)
+	if err == nil || valid {
+		t.Errorf("signature with segwit header should be rejected for a P2PKH address")
+	}
+	// but it still recovers the same public key
+	pubKey, segwitHeader, err := recoverMessagePubKey("This is an example of a signed message.", electrum)
+	legacyPubKey, _, _ := recoverMessagePubKey("This is an example of a signed message.", base64.StdEncoding.EncodeToString(signature))
+	if err != nil || !segwitHeader || !bytes.Equal(pubKey, legacyPubKey) {
+		t.Errorf("segwit header should recover the same public key, got %x %v %v", pubKey, segwitHeader, err)
+	}
+}
@@ -0,0 +1,33 @@
+package legacy
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/savardiego/cashline/keys"
+)
+
+// FindAddressReuse derives the P2PKH address of every private key and returns the addresses shared by more than one key,
+// each with the indexes of the keys producing it: any entry means the same key appears more than once in the set
+func FindAddressReuse(privKeys [][]byte, compressed bool, network keys.Network) (map[string][]int, error) {
+	indexes := make(map[string][]int)
+	for i, privKey := range privKeys {
+		if len(privKey) != 32 {
+			return nil, fmt.Errorf("key at index %d is %d bytes long, must be 32", i, len(privKey))
+		}
+		value := new(big.Int).SetBytes(privKey)
+		if value.Sign() == 0 || value.Cmp(btcec.S256().N) >= 0 {
+			return nil, fmt.Errorf("key at index %d is not acceptable as private key", i)
+		}
+		address := fromHash(network.PubKeyHashVersion(), keys.Hashed(keys.Public(privKey, compressed)))
+		indexes[address] = append(indexes[address], i)
+	}
+	reused := make(map[string][]int)
+	for address, keyIndexes := range indexes {
+		if len(keyIndexes) > 1 {
+			reused[address] = keyIndexes
+		}
+	}
+	return reused, nil
+}
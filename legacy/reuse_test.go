@@ -0,0 +1,50 @@
+package legacy
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/savardiego/cashline/keys"
+)
+
+func TestFindAddressReuse(t *testing.T) {
+	one, _ := hex.DecodeString("0C28FCA386C7A227600B2FE50B7CAE11EC86D3BF1FBE471BE89827E19D72AA1D")
+	two, _ := hex.DecodeString("18e14a7b6a307f426a94f8114701e7c8e774e7f9a47e2c2035db29a206321725")
+	reused, err := FindAddressReuse([][]byte{one, two}, true, keys.MainNet)
+	if err != nil {
+		t.Errorf("failed due to %v\n", err)
+	}
+	if len(reused) != 0 {
+		t.Errorf("distinct keys should not be reported as reused: %v", reused)
+	}
+	reused, err = FindAddressReuse([][]byte{one, two, one, two, one}, true, keys.MainNet)
+	if err != nil {
+		t.Errorf("failed due to %v\n", err)
+	}
+	expected := map[string][]int{
+		"1LoVGDgRs9hTfTNJNuXKSpywcbdvwRXpmK": []int{0, 2, 4},
+		"1PMycacnJaSqwwJqjawXBErnLsZ7RkXUAs": []int{1, 3},
+	}
+	if len(reused) != len(expected) {
+		t.Errorf("reused addresses should be %v but are %v", expected, reused)
+	}
+	for address, indexes := range expected {
+		got := reused[address]
+		if len(got) != len(indexes) {
+			t.Errorf("address %v should be reused by keys %v but is by %v", address, indexes, got)
+			continue
+		}
+		for i := range indexes {
+			if got[i] != indexes[i] {
+				t.Errorf("address %v should be reused by keys %v but is by %v", address, indexes, got)
+				break
+			}
+		}
+	}
+	if _, err = FindAddressReuse([][]byte{one, make([]byte, 32)}, true, keys.MainNet); err == nil {
+		t.Errorf("zero key should return an error")
+	}
+	if _, err = FindAddressReuse([][]byte{one[:31]}, true, keys.MainNet); err == nil {
+		t.Errorf("short key should return an error")
+	}
+}
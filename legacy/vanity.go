@@ -0,0 +1,103 @@
+package legacy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/savardiego/cashline/keys"
+)
+
+type vanityResult struct {
+	privKey []byte
+	address string
+	err     error
+}
+
+// FindVanitySuffix searches, with the given number of parallel workers, a random private key whose compressed P2PKH address ends with suffix.
+// The last chars of an address depend on the checksum, so every char of the suffix is as hard to match as a prefix char:
+// each one multiplies the expected attempts by 58. The search goes on until a match is found or ctx is done.
+// With workers 0 or less it uses keys.OptimalWorkerCount() workers
+func FindVanitySuffix(suffix string, network keys.Network, workers int, ctx context.Context) (privKey []byte, address string, err error) {
+	if len(suffix) == 0 {
+		return nil, "", errors.New("suffix cannot be empty")
+	}
+	if err := checkBase58(suffix); err != nil {
+		return nil, "", err
+	}
+	return searchVanity(ctx, network, workers, func(address string) bool {
+		return strings.HasSuffix(address, suffix)
+	})
+}
+
+// FindVanity generates random private keys until the mainnet P2PKH address of one of them (of the compressed or uncompressed public key)
+// starts with "1" followed by prefix, giving up after maxAttempts keys. Every char of the prefix multiplies the expected attempts by 58,
+// and a first char above Q is much harder: most addresses are 34 chars long, and those can only continue with 2 to Q
+func FindVanity(prefix string, compressed bool, maxAttempts int) (privKey []byte, address string, attempts int, err error) {
+	if len(prefix) == 0 {
+		return nil, "", 0, errors.New("prefix cannot be empty")
+	}
+	if err := checkBase58(prefix); err != nil {
+		return nil, "", 0, err
+	}
+	if maxAttempts < 1 {
+		return nil, "", 0, fmt.Errorf("max attempts must be at least 1, got %d", maxAttempts)
+	}
+	for attempts = 1; attempts <= maxAttempts; attempts++ {
+		privKey, err = keys.Random()
+		if err != nil {
+			return nil, "", attempts, err
+		}
+		address = fromHash(keys.MainNet.PubKeyHashVersion(), keys.Hashed(keys.Public(privKey, compressed)))
+		if strings.HasPrefix(address[1:], prefix) {
+			return privKey, address, attempts, nil
+		}
+	}
+	return nil, "", maxAttempts, fmt.Errorf("no address starting with 1%v found in %d attempts", prefix, maxAttempts)
+}
+
+// searchVanity generates random keys in parallel until the P2PKH address of one of them satisfies match
+func searchVanity(ctx context.Context, network keys.Network, workers int, match func(address string) bool) ([]byte, string, error) {
+	if workers < 1 {
+		workers = keys.OptimalWorkerCount()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// every worker sends at most one result, so they never block once the search is over
+	results := make(chan vanityResult, workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			for ctx.Err() == nil {
+				key, err := btcec.NewPrivateKey(btcec.S256())
+				if err != nil {
+					results <- vanityResult{err: fmt.Errorf("cannot generate private key due to %v", err)}
+					return
+				}
+				hashed := keys.Hashed(key.PubKey().SerializeCompressed())
+				address := fromHash(network.PubKeyHashVersion(), hashed)
+				if match(address) {
+					results <- vanityResult{privKey: key.Serialize(), address: address}
+					return
+				}
+			}
+		}()
+	}
+	select {
+	case result := <-results:
+		return result.privKey, result.address, result.err
+	case <-ctx.Done():
+		return nil, "", fmt.Errorf("vanity search stopped: %v", ctx.Err())
+	}
+}
+
+// checkBase58 returns an error naming the first char of s that is not in the base58 alphabet
+func checkBase58(s string) error {
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(keys.Base58Alphabet(), s[i]) < 0 {
+			return fmt.Errorf("char %c at position %d is not allowed in base58", s[i], i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package legacy
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/savardiego/cashline/keys"
+)
+
+func TestFindVanitySuffix(t *testing.T) {
+	for _, network := range keys.Networks {
+		privKey, address, err := FindVanitySuffix("z", network, 2, context.Background())
+		if err != nil {
+			t.Errorf("vanity search failed due to %v", err)
+			continue
+		}
+		if !strings.HasSuffix(address, "z") {
+			t.Errorf("address %v does not end with z", address)
+		}
+		wif, err := keys.ToWIFNetwork(privKey, true, network)
+		if err != nil {
+			t.Errorf("cannot encode key due to %v", err)
+		}
+		forms, err := AllNetworkForms(privKey, true)
+		if err != nil {
+			t.Errorf("cannot derive address due to %v", err)
+		}
+		if forms[network.String()].Address != address || forms[network.String()].WIF != wif {
+			t.Errorf("key %v does not match address %v", wif, address)
+		}
+	}
+}
+
+func TestFindVanitySuffixDefaultWorkers(t *testing.T) {
+	// 0 or fewer workers fall back to keys.OptimalWorkerCount()
+	for _, workers := range []int{0, -1} {
+		_, address, err := FindVanitySuffix("z", keys.MainNet, workers, context.Background())
+		if err != nil {
+			t.Errorf("search with %d workers failed due to %v", workers, err)
+			continue
+		}
+		if !strings.HasSuffix(address, "z") {
+			t.Errorf("address %v does not end with z", address)
+		}
+	}
+}
+
+func TestFindVanitySuffixErrors(t *testing.T) {
+	for _, suffix := range []string{"", "0", "O", "I", "l", "ab0"} {
+		if _, _, err := FindVanitySuffix(suffix, keys.MainNet, 1, context.Background()); err == nil {
+			t.Errorf("suffix %v should be rejected", suffix)
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := FindVanitySuffix("zzzzzz", keys.MainNet, 1, ctx); err == nil {
+		t.Errorf("cancelled search should return an error")
+	}
+}
+
+func TestFindVanity(t *testing.T) {
+	for _, compressed := range []bool{true, false} {
+		privKey, address, attempts, err := FindVanity("C", compressed, 10000)
+		if err != nil {
+			t.Errorf("vanity search failed due to %v", err)
+			continue
+		}
+		if !strings.HasPrefix(address, "1C") || attempts < 1 {
+			t.Errorf("address %v found in %d attempts does not start with 1C", address, attempts)
+		}
+		expected, _ := keys.AddressFromPrivate(privKey, compressed, false)
+		if expected != address {
+			t.Errorf("key does not match address %v, its address is %v", address, expected)
+		}
+	}
+	for _, prefix := range []string{"", "0", "z0"} {
+		if _, _, _, err := FindVanity(prefix, true, 10); err == nil {
+			t.Errorf("prefix %v should be rejected", prefix)
+		}
+	}
+	if _, _, attempts, err := FindVanity("zzzzzz", true, 5); err == nil || attempts != 5 {
+		t.Errorf("search should give up after 5 attempts, got %d attempts and %v", attempts, err)
+	}
+}
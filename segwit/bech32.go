@@ -0,0 +1,138 @@
+package segwit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// charset is the alphabet of the bech32 data part, the index of each char is its 5 bit value
+const charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// bech32Const is the checksum constant of bech32 (BIP173), used for witness version 0
+const bech32Const = 1
+
+// bech32mConst is the checksum constant of bech32m (BIP350), used for witness version 1 and above
+const bech32mConst = 0x2bc830a3
+
+// maxLength is the maximum length of a bech32 string
+const maxLength = 90
+
+// polyMod calculates the 30 bit BCH checksum of bech32
+// Reference: https://github.com/bitcoin/bips/blob/master/bip-0173.mediawiki#checksum
+func polyMod(values []byte) uint32 {
+	generator := []uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+// hrpExpand returns the high bits of every char of the human readable part, a 0 separator and the low bits of every char
+func hrpExpand(hrp string) []byte {
+	ret := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		ret = append(ret, hrp[i]>>5)
+	}
+	ret = append(ret, 0)
+	for i := 0; i < len(hrp); i++ {
+		ret = append(ret, hrp[i]&31)
+	}
+	return ret
+}
+
+// createChecksum returns the 6 checksum values (5 bit each) of the human readable part and the data
+func createChecksum(hrp string, data []byte, constant uint32) []byte {
+	values := append(hrpExpand(hrp), data...)
+	values = append(values, []byte{0, 0, 0, 0, 0, 0}...)
+	mod := polyMod(values) ^ constant
+	checksum := make([]byte, 6)
+	for i := 0; i < 6; i++ {
+		checksum[i] = byte((mod >> uint(5*(5-i))) & 31)
+	}
+	return checksum
+}
+
+// encode returns the bech32 (or bech32m, depending on the constant) string of the human readable part and the 5 bit data
+func encode(hrp string, data []byte, constant uint32) (string, error) {
+	combined := append(append([]byte{}, data...), createChecksum(hrp, data, constant)...)
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, v := range combined {
+		if int(v) >= len(charset) {
+			return "", fmt.Errorf("value not allowed for bech32 encoding %d", v)
+		}
+		sb.WriteByte(charset[v])
+	}
+	return sb.String(), nil
+}
+
+// decode splits a bech32 or bech32m string in the human readable part and the 5 bit data (checksum excluded),
+// returning also the checksum constant it was encoded with
+func decode(bech string) (hrp string, data []byte, constant uint32, err error) {
+	if len(bech) > maxLength {
+		return "", nil, 0, fmt.Errorf("invalid bech32 string length %d, must be at most %d", len(bech), maxLength)
+	}
+	lower := strings.ToLower(bech)
+	if lower != bech && strings.ToUpper(bech) != bech {
+		return "", nil, 0, fmt.Errorf("bech32 string %v mixes lower and upper case", bech)
+	}
+	separator := strings.LastIndexByte(lower, '1')
+	if separator < 1 || separator+7 > len(lower) {
+		return "", nil, 0, fmt.Errorf("invalid position of the separator in bech32 string %v", bech)
+	}
+	hrp = lower[:separator]
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", nil, 0, fmt.Errorf("char not allowed in the human readable part %c", hrp[i])
+		}
+	}
+	values := make([]byte, 0, len(lower)-separator-1)
+	for i := separator + 1; i < len(lower); i++ {
+		v := strings.IndexByte(charset, lower[i])
+		if v < 0 {
+			return "", nil, 0, fmt.Errorf("char not allowed in the bech32 data %c", lower[i])
+		}
+		values = append(values, byte(v))
+	}
+	constant = polyMod(append(hrpExpand(hrp), values...))
+	if constant != bech32Const && constant != bech32mConst {
+		return "", nil, 0, fmt.Errorf("invalid checksum for bech32 string %v", bech)
+	}
+	return hrp, values[:len(values)-6], constant, nil
+}
+
+// convertBits regroups an array of byte with inSize bits to an array of byte with toSize bits
+func convertBits(data []byte, inSize uint, toSize uint, pad bool) ([]byte, error) {
+	var accumulator uint
+	var bits uint
+	maxValue := uint(1<<toSize) - 1
+	result := make([]byte, 0, len(data)*int(inSize)/int(toSize)+1)
+	for _, b := range data {
+		value := uint(b)
+		if value>>inSize != 0 {
+			return nil, fmt.Errorf("invalid value %x for %d bits", value, inSize)
+		}
+		accumulator = (accumulator << inSize) | value
+		bits += inSize
+		for bits >= toSize {
+			bits -= toSize
+			result = append(result, byte((accumulator>>bits)&maxValue))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			result = append(result, byte((accumulator<<(toSize-bits))&maxValue))
+		}
+	} else if bits >= inSize || (accumulator<<(toSize-bits))&maxValue != 0 {
+		return nil, fmt.Errorf("input cannot be converted to %d bits without padding", toSize)
+	}
+	return result, nil
+}
@@ -0,0 +1,81 @@
+package segwit
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBech32Checksum(t *testing.T) {
+	// https://github.com/bitcoin/bips/blob/master/bip-0173.mediawiki#test-vectors
+	// https://github.com/bitcoin/bips/blob/master/bip-0350.mediawiki#test-vectors-for-bech32m
+	valid := map[string]uint32{
+		"A12UEL5L": bech32Const,
+		"a12uel5l": bech32Const,
+		"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw":                bech32Const,
+		"split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w": bech32Const,
+		"A1LQFN3A": bech32mConst,
+		"abcdef1l7aum6echk45nj3s0wdvt2fg8x9yrzpqzd3ryx": bech32mConst,
+	}
+	for s, expected := range valid {
+		_, _, constant, err := decode(s)
+		if err != nil {
+			t.Errorf("cannot decode %v due to %v", s, err)
+			continue
+		}
+		if constant != expected {
+			t.Errorf("%v should have checksum constant %x but has %x", s, expected, constant)
+		}
+	}
+	invalid := []string{
+		"pzry9x0s0muk",
+		"1pzry9x0s0muk",
+		"x1b4n0q5v",
+		"li1dgmt3",
+		"A1G7SGD8",
+		"a12UEL5L",
+		"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxx",
+	}
+	for _, s := range invalid {
+		if _, _, _, err := decode(s); err == nil {
+			t.Errorf("invalid bech32 string %v was accepted", s)
+		}
+	}
+}
+
+func TestBech32EncodeDecode(t *testing.T) {
+	data := []byte{0, 14, 20, 15, 7, 13, 26, 0, 25, 18, 6, 11, 13, 8, 21, 4, 20, 3, 17, 2, 29, 3, 12, 29, 3, 4, 15, 24, 20, 6, 14, 30, 22}
+	for _, constant := range []uint32{bech32Const, bech32mConst} {
+		encoded, err := encode("bc", data, constant)
+		if err != nil {
+			t.Errorf("failed during encoding due to %v", err)
+		}
+		hrp, decoded, decodedConstant, err := decode(encoded)
+		if err != nil {
+			t.Errorf("failed during decoding due to %v", err)
+		}
+		if hrp != "bc" || !bytes.Equal(decoded, data) || decodedConstant != constant {
+			t.Errorf("encoding and decoding led to a different value: %v %v %x", hrp, decoded, decodedConstant)
+		}
+	}
+}
+
+func TestConvertBits(t *testing.T) {
+	program := []byte{0x75, 0x1e, 0x76, 0xe8, 0x19, 0x91, 0x96, 0xd4, 0x54, 0x94, 0x1c, 0x45, 0xd1, 0xb3, 0xa3, 0x23, 0xf1, 0x43, 0x3b, 0xd6}
+	fiveBits, err := convertBits(program, 8, 5, true)
+	if err != nil {
+		t.Errorf("Unexpected failure: %v\n", err)
+	}
+	eightBits, err := convertBits(fiveBits, 5, 8, false)
+	if err != nil {
+		t.Errorf("Unexpected failure: %v\n", err)
+	}
+	if !bytes.Equal(program, eightBits) {
+		t.Errorf("Gone and return conversion should bring to the original array: %x %x", program, eightBits)
+	}
+	if _, err := convertBits([]byte{32}, 5, 8, false); err == nil {
+		t.Errorf("Should fail when data contains invalid values.")
+	}
+	if _, err := convertBits([]byte{1, 1}, 5, 8, false); err == nil {
+		t.Errorf("Should fail when padding is needed but not allowed.")
+	}
+}
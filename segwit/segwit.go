@@ -0,0 +1,72 @@
+// Package segwit encodes and decodes SegWit (bech32, BIP173) and Taproot (bech32m, BIP350) addresses
+package segwit
+
+import (
+	"fmt"
+)
+
+// Encode returns the address of a witness program with the given version, bech32 encoded for version 0 and bech32m for later versions
+func Encode(hrp string, version byte, program []byte) (string, error) {
+	if version > 16 {
+		return "", fmt.Errorf("invalid witness version %d", version)
+	}
+	if err := checkProgramLength(version, program); err != nil {
+		return "", err
+	}
+	data, err := convertBits(program, 8, 5, true)
+	if err != nil {
+		return "", fmt.Errorf("cannot convert program to 5 bit due to %v", err)
+	}
+	constant := uint32(bech32mConst)
+	if version == 0 {
+		constant = bech32Const
+	}
+	return encode(hrp, append([]byte{version}, data...), constant)
+}
+
+// Decode returns the witness version and program of an address, checking it has the expected human readable part
+func Decode(hrp string, address string) (version byte, program []byte, err error) {
+	decodedHrp, data, constant, err := decode(address)
+	if err != nil {
+		return 0, nil, err
+	}
+	if decodedHrp != hrp {
+		return 0, nil, fmt.Errorf("address human readable part is %v, expected %v", decodedHrp, hrp)
+	}
+	if len(data) < 1 {
+		return 0, nil, fmt.Errorf("address %v has no witness version", address)
+	}
+	version = data[0]
+	if version > 16 {
+		return 0, nil, fmt.Errorf("invalid witness version %d", version)
+	}
+	if version == 0 && constant != bech32Const {
+		return 0, nil, fmt.Errorf("witness v0 address must use bech32 checksum")
+	}
+	if version != 0 && constant != bech32mConst {
+		return 0, nil, fmt.Errorf("witness v%d address must use bech32m checksum", version)
+	}
+	program, err = convertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return 0, nil, fmt.Errorf("cannot convert program to 8 bit due to %v", err)
+	}
+	if err := checkProgramLength(version, program); err != nil {
+		return 0, nil, err
+	}
+	return version, program, nil
+}
+
+// checkProgramLength enforces the length rules of witness programs: 2 to 40 bytes, 20 or 32 bytes for v0 (P2WPKH, P2WSH)
+// and 32 bytes for v1 (Taproot)
+func checkProgramLength(version byte, program []byte) error {
+	if len(program) < 2 || len(program) > 40 {
+		return fmt.Errorf("invalid witness program length %d", len(program))
+	}
+	if version == 0 && len(program) != 20 && len(program) != 32 {
+		return fmt.Errorf("witness v0 program must be 20 or 32 bytes, got %d", len(program))
+	}
+	if version == 1 && len(program) != 32 {
+		return fmt.Errorf("witness v1 program must be 32 bytes, got %d", len(program))
+	}
+	return nil
+}
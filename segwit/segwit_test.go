@@ -0,0 +1,101 @@
+package segwit
+
+import (
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	// https://github.com/bitcoin/bips/blob/master/bip-0173.mediawiki#test-vectors
+	// https://github.com/bitcoin/bips/blob/master/bip-0350.mediawiki#test-vectors-for-v0-v16-native-segregated-witness-addresses
+	// Hrp, address, version, program
+	addresses := [][]string{
+		[]string{"bc", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", "0", "751e76e8199196d454941c45d1b3a323f1433bd6"},
+		[]string{"tb", "tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7", "0", "1863143c14c5166804bd19203356da136c985678cd4d27a1b8c6329604903262"},
+		[]string{"bc", "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", "1", "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"},
+	}
+	for _, v := range addresses {
+		program, _ := hex.DecodeString(v[3])
+		version := byte(v[2][0] - '0')
+		address, err := Encode(v[0], version, program)
+		if err != nil {
+			t.Errorf("cannot encode %v due to %v", v[1], err)
+		}
+		if address != v[1] {
+			t.Errorf("address should be %v but is %v", v[1], address)
+		}
+		decodedVersion, decodedProgram, err := Decode(v[0], strings.ToUpper(v[1]))
+		if err != nil {
+			t.Errorf("cannot decode %v due to %v", v[1], err)
+		}
+		if decodedVersion != version || hex.EncodeToString(decodedProgram) != v[3] {
+			t.Errorf("%v decoded to version %d program %x", v[1], decodedVersion, decodedProgram)
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	program, _ := hex.DecodeString("79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	data, _ := convertBits(program, 8, 5, true)
+	v1WithBech32, _ := encode("bc", append([]byte{1}, data...), bech32Const)
+	v0WithBech32m, _ := encode("bc", append([]byte{0}, data...), bech32mConst)
+	invalid := [][]string{
+		[]string{"tb", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"},
+		[]string{"bc", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5"},
+		[]string{"bc", v1WithBech32},
+		[]string{"bc", v0WithBech32m},
+	}
+	for _, v := range invalid {
+		if _, _, err := Decode(v[0], v[1]); err == nil {
+			t.Errorf("invalid address %v was accepted", v[1])
+		}
+	}
+	if _, err := Encode("bc", 0, program[:21]); err == nil {
+		t.Errorf("witness v0 program of 21 bytes should be rejected")
+	}
+	if _, err := Encode("bc", 17, program); err == nil {
+		t.Errorf("witness version 17 should be rejected")
+	}
+}
+
+func TestProgramLengthRules(t *testing.T) {
+	program := make([]byte, 40)
+	// Version, program length, expected error (empty if the length is valid)
+	rules := [][]string{
+		[]string{"0", "20", ""},
+		[]string{"0", "32", ""},
+		[]string{"0", "21", "witness v0 program must be 20 or 32 bytes, got 21"},
+		[]string{"1", "32", ""},
+		[]string{"1", "20", "witness v1 program must be 32 bytes, got 20"},
+		[]string{"1", "40", "witness v1 program must be 32 bytes, got 40"},
+		[]string{"2", "40", ""},
+		[]string{"2", "1", "invalid witness program length 1"},
+	}
+	for _, v := range rules {
+		version := byte(v[0][0] - '0')
+		length, _ := strconv.Atoi(v[1])
+		_, err := Encode("bc", version, program[:length])
+		if v[2] == "" && err != nil {
+			t.Errorf("v%v program of %v bytes should be accepted but failed due to %v", v[0], v[1], err)
+		}
+		if v[2] != "" && (err == nil || err.Error() != v[2]) {
+			t.Errorf("v%v program of %v bytes should fail with %q, got %v", v[0], v[1], v[2], err)
+		}
+		// build the address bypassing Encode, to check that Decode enforces the same rules
+		data, _ := convertBits(program[:length], 8, 5, true)
+		constant := uint32(bech32mConst)
+		if version == 0 {
+			constant = bech32Const
+		}
+		address, _ := encode("bc", append([]byte{version}, data...), constant)
+		_, _, err = Decode("bc", address)
+		if v[2] == "" && err != nil {
+			t.Errorf("v%v address of %v bytes should be accepted but failed due to %v", v[0], v[1], err)
+		}
+		if v[2] != "" && (err == nil || err.Error() != v[2]) {
+			t.Errorf("v%v address of %v bytes should fail with %q, got %v", v[0], v[1], v[2], err)
+		}
+	}
+}